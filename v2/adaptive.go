@@ -0,0 +1,164 @@
+package rungroup
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// AdaptiveLimit configures the adaptive limit set by [Group.SetAdaptiveLimit].
+type AdaptiveLimit struct {
+	// Min and Max bound the limit. Min defaults to 1, and a Max of zero means
+	// no upper bound.
+	Min, Max int
+
+	// Initial is the limit to start with. It defaults to Min.
+	Initial int
+
+	// Latency is the duration above which a task that succeeded is treated
+	// as a sign of congestion, as if it had failed. Zero disables the
+	// latency signal, so that only errors decrease the limit.
+	Latency time.Duration
+
+	// Backoff is the factor by which the limit is multiplied when a task
+	// fails or is too slow. It defaults to 0.9, and must be between 0 and 1.
+	Backoff float64
+}
+
+// SetAdaptiveLimit limits the number of active tasks in the [Group] with a
+// limit that adapts to how the tasks perform, using additive increase and
+// multiplicative decrease (AIMD). A nil cfg removes the adaptive limit, which
+// is the default.
+//
+// Each task that succeeds within cfg.Latency increases the limit by 1/limit,
+// i.e. by about one per limit's worth of tasks, up to cfg.Max. Each task
+// that fails, panics, or takes longer than cfg.Latency multiplies the limit by
+// cfg.Backoff, down to cfg.Min. The tasks that finish after the [Group] has
+// been canceled are not taken into account. The error returned by a task
+// still cancels the [Group] according to its [Policy].
+//
+// While the limit is reached, [Group.Go] and the other methods that start a
+// task block until a task finishes, whereas [Group.TryGo] returns false, as
+// they do for the limit set by [Group.SetLimit], which applies as well. The
+// current limit and its changes are reported by [Group.Stats].
+//
+// Use Cases:
+//
+// Use this for tasks that call a downstream dependency whose capacity varies,
+// where a static limit is either too low or overloads the dependency.
+func (gr *Group) SetAdaptiveLimit(cfg *AdaptiveLimit) {
+	if cfg == nil {
+		gr.adaptive.Store(nil)
+		return
+	}
+	a := &adaptive{cfg: *cfg}
+	if a.cfg.Min < 1 {
+		a.cfg.Min = 1
+	}
+	if a.cfg.Max <= 0 {
+		a.cfg.Max = math.MaxInt32
+	}
+	if a.cfg.Max < a.cfg.Min {
+		a.cfg.Max = a.cfg.Min
+	}
+	if a.cfg.Backoff <= 0 || a.cfg.Backoff >= 1 {
+		a.cfg.Backoff = 0.9
+	}
+	a.limit = float64(a.cfg.Initial)
+	a.clamp()
+	a.cond.L = &a.mu
+	gr.adaptive.Store(a)
+}
+
+// acquireAdaptive reserves a place for a task within the adaptive limit,
+// waiting for one unless try is set. It returns the limiter, which is nil if
+// there is no adaptive limit, and reports whether the place has been
+// reserved.
+func (gr *Group) acquireAdaptive(try bool) (*adaptive, bool) {
+	a := gr.adaptive.Load()
+	if a == nil {
+		return nil, true
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for a.active >= int(a.limit) {
+		if try {
+			return nil, false
+		}
+		a.cond.Wait()
+	}
+	a.active++
+	return a, true
+}
+
+// adaptive is an AIMD concurrency limiter.
+type adaptive struct {
+	cfg       AdaptiveLimit
+	mu        sync.Mutex
+	cond      sync.Cond
+	limit     float64
+	active    int
+	increases int64
+	decreases int64
+}
+
+// release frees the place of a task. It does nothing if a is nil.
+func (a *adaptive) release() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	a.active--
+	a.mu.Unlock()
+	a.cond.Signal()
+}
+
+// observe adjusts the limit after a task that took d, and that failed if
+// failed is true. It does nothing if a is nil.
+func (a *adaptive) observe(d time.Duration, failed bool) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	old := int(a.limit)
+	if failed || (a.cfg.Latency > 0 && d > a.cfg.Latency) {
+		a.limit *= a.cfg.Backoff
+	} else {
+		a.limit += 1 / a.limit
+	}
+	a.clamp()
+	n := int(a.limit)
+	switch {
+	case n > old:
+		a.increases++
+	case n < old:
+		a.decreases++
+	}
+	a.mu.Unlock()
+	if n > old {
+		a.cond.Broadcast()
+	}
+}
+
+// clamp keeps the limit within the bounds.
+//
+// a.mu must be held.
+func (a *adaptive) clamp() {
+	if a.limit < float64(a.cfg.Min) {
+		a.limit = float64(a.cfg.Min)
+	}
+	if a.limit > float64(a.cfg.Max) {
+		a.limit = float64(a.cfg.Max)
+	}
+}
+
+// stats returns the current limit and the number of times it has increased
+// and decreased. It returns zeros if a is nil.
+func (a *adaptive) stats() (limit int, increases, decreases int64) {
+	if a == nil {
+		return 0, 0, 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return int(a.limit), a.increases, a.decreases
+}
@@ -0,0 +1,97 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/rungroup/v2/rungrouptest"
+)
+
+func TestGroup_SetAdaptiveLimit(t *testing.T) {
+	ErrFail := errors.New("fail")
+	run := func(gr *rungroup.Group, task func(context.Context) error) {
+		gr.GoNamed("", rungroup.CancelNever, task)
+		gr.Wait()
+	}
+	succeed := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return ErrFail }
+
+	t.Run("additive increase", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetAdaptiveLimit(&rungroup.AdaptiveLimit{Initial: 2, Max: 3})
+		assertEqual(t, gr.Stats().Limit, 2)
+		for i := 0; i < 3; i++ { // 2 + 1/2 + 1/2.5 + 1/2.9 = 3.24
+			run(&gr, succeed)
+		}
+		st := gr.Stats()
+		assertEqual(t, st.Limit, 3)
+		assertEqual(t, st.LimitIncreases, int64(1))
+		for i := 0; i < 10; i++ {
+			run(&gr, succeed)
+		}
+		assertEqual(t, gr.Stats().Limit, 3)
+	})
+
+	t.Run("multiplicative decrease", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetAdaptiveLimit(&rungroup.AdaptiveLimit{Min: 2, Initial: 8, Backoff: 0.5})
+		run(&gr, fail)
+		st := gr.Stats()
+		assertEqual(t, st.Limit, 4)
+		assertEqual(t, st.LimitDecreases, int64(1))
+		run(&gr, func(context.Context) error { panic("boom") })
+		run(&gr, fail)
+		assertEqual(t, gr.Stats().Limit, 2)
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		clock := rungrouptest.NewClock(time.Unix(0, 0))
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetClock(clock)
+		gr.SetAdaptiveLimit(&rungroup.AdaptiveLimit{Initial: 10, Latency: time.Second, Backoff: 0.5})
+		run(&gr, func(context.Context) error {
+			clock.Advance(time.Second)
+			return nil
+		})
+		assertEqual(t, gr.Stats().Limit, 10)
+		run(&gr, func(context.Context) error {
+			clock.Advance(2 * time.Second)
+			return nil
+		})
+		assertEqual(t, gr.Stats().Limit, 5)
+	})
+
+	t.Run("limits active tasks", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetAdaptiveLimit(&rungroup.AdaptiveLimit{Max: 1})
+		release := make(chan struct{})
+		assertEqual(t, gr.TryGo(func(context.Context) { <-release }), true)
+		assertEqual(t, gr.TryGo(func(context.Context) {}), false)
+		close(release)
+		gr.Wait()
+		assertEqual(t, gr.TryGo(func(context.Context) {}), true)
+	})
+
+	t.Run("errors still cancel the group", func(t *testing.T) {
+		var gr rungroup.Group
+		gr.SetAdaptiveLimit(&rungroup.AdaptiveLimit{Initial: 4, Backoff: 0.5})
+		gr.GoCancelOnError(fail)
+		assertErrorIs(t, gr.Wait(), ErrFail)
+		assertEqual(t, gr.Stats().Limit, 2)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetAdaptiveLimit(&rungroup.AdaptiveLimit{Max: 1})
+		gr.SetAdaptiveLimit(nil)
+		assertEqual(t, gr.Stats().Limit, 0)
+	})
+}
@@ -0,0 +1,100 @@
+package rungroup_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/waitgroup"
+)
+
+// The benchmarks spawn b.N tiny tasks. To measure a million tasks, run:
+//
+//	go test -run '^$' -bench . -benchtime 1000000x
+
+func BenchmarkGroup_Go(b *testing.B) {
+	b.ReportAllocs()
+	var gr rungroup.Group
+	defer gr.Close()
+	for i := 0; i < b.N; i++ {
+		gr.Go(func(context.Context) {})
+	}
+	gr.Wait()
+}
+
+func BenchmarkGroup_GoCancelOnError(b *testing.B) {
+	b.ReportAllocs()
+	var gr rungroup.Group
+	defer gr.Close()
+	for i := 0; i < b.N; i++ {
+		gr.GoCancelOnError(func(context.Context) error { return nil })
+	}
+	gr.Wait()
+}
+
+func BenchmarkGroup_GoCancelOnError_noCallers(b *testing.B) {
+	b.ReportAllocs()
+	var gr rungroup.Group
+	defer gr.Close()
+	gr.SetCallerDepth(0)
+	for i := 0; i < b.N; i++ {
+		gr.GoCancelOnError(func(context.Context) error { return nil })
+	}
+	gr.Wait()
+}
+
+// BenchmarkWaitgroup is the baseline: plain goroutines without a Group.
+func BenchmarkWaitgroup(b *testing.B) {
+	b.ReportAllocs()
+	var g waitgroup.Sync
+	for i := 0; i < b.N; i++ {
+		g.Go(func() {})
+	}
+	g.Wait()
+}
+
+func BenchmarkGroup_Go_pool(b *testing.B) {
+	b.ReportAllocs()
+	var gr rungroup.Group
+	defer gr.Close()
+	gr.SetPool(time.Second)
+	for i := 0; i < b.N; i++ {
+		gr.Go(func(context.Context) {})
+	}
+	gr.Wait()
+}
+
+// deepStack is a task that grows its goroutine stack, which a new goroutine
+// has to do again while a goroutine of a pool has done it already.
+func deepStack(context.Context) { recurse(64) }
+
+//go:noinline
+func recurse(n int) byte {
+	var buf [256]byte
+	if n == 0 {
+		return buf[0]
+	}
+	return recurse(n-1) + buf[n]
+}
+
+func BenchmarkGroup_Go_deepStack(b *testing.B) {
+	b.ReportAllocs()
+	var gr rungroup.Group
+	defer gr.Close()
+	for i := 0; i < b.N; i++ {
+		gr.Go(deepStack)
+	}
+	gr.Wait()
+}
+
+func BenchmarkGroup_Go_deepStack_pool(b *testing.B) {
+	b.ReportAllocs()
+	var gr rungroup.Group
+	defer gr.Close()
+	gr.SetPool(time.Second)
+	for i := 0; i < b.N; i++ {
+		gr.Go(deepStack)
+	}
+	gr.Wait()
+}
@@ -0,0 +1,235 @@
+package rungroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is matched, with [errors.Is], by the error of a task that
+// is not run because the circuit breaker of its name is open. See
+// [Group.SetCircuitBreaker].
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitState is the state of the circuit breaker of a task name.
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Tasks run normally.
+	CircuitOpen                         // Tasks fail fast with ErrCircuitOpen.
+	CircuitHalfOpen                     // A trial task runs; the others fail fast.
+)
+
+// String returns the name of s.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreaker configures the circuit breakers set by
+// [Group.SetCircuitBreaker].
+type CircuitBreaker struct {
+	// Failures is the number of consecutive failures of the tasks of a name
+	// that opens its circuit. It defaults to 5.
+	Failures int
+
+	// Cooldown is how long a circuit stays open before a trial task is let
+	// through. It defaults to 30 seconds.
+	Cooldown time.Duration
+
+	// Successes is the number of consecutive trial tasks that must succeed to
+	// close a half-open circuit. It defaults to 1.
+	Successes int
+}
+
+// SetCircuitBreaker sets a circuit breaker for each task name, such as the
+// names given to [Group.GoNamed] and [Group.GoTask]. Tasks without a name are
+// not affected. A nil cfg removes the circuit breakers, which is the default.
+//
+// A circuit starts closed. After cfg.Failures tasks of the name have failed
+// or panicked in a row, it opens: the tasks of the name started while it is
+// open do not run, but fail immediately with an error that matches
+// [ErrCircuitOpen], without waiting for the limits of the [Group]. The error
+// is handled as if the task had returned it, so it is counted in [Stats] and
+// cancels the [Group] according to the [Policy] of the task. After
+// cfg.Cooldown, the circuit becomes half-open, and lets one trial task run at
+// a time: it closes once cfg.Successes trial tasks have succeeded in a row,
+// and opens again when a trial task fails.
+//
+// The tasks that finish after the [Group] has been canceled are not taken
+// into account. The state changes are reported to [Hooks].CircuitChanged,
+// and the current state of each name is reported in [TaskStats].
+//
+// Use Cases:
+//
+// Use this when tasks that call a dependency are restarted in a loop, so that
+// they stop hammering the dependency while it is down.
+func (gr *Group) SetCircuitBreaker(cfg *CircuitBreaker) {
+	if cfg == nil {
+		gr.breaker.Store(nil)
+		return
+	}
+	b := &breaker{cfg: *cfg, circuits: map[string]*circuit{}}
+	if b.cfg.Failures < 1 {
+		b.cfg.Failures = 5
+	}
+	if b.cfg.Cooldown <= 0 {
+		b.cfg.Cooldown = 30 * time.Second
+	}
+	if b.cfg.Successes < 1 {
+		b.cfg.Successes = 1
+	}
+	gr.breaker.Store(b)
+}
+
+// breaker holds the circuits of the task names.
+type breaker struct {
+	cfg      CircuitBreaker
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is the circuit breaker of a task name.
+type circuit struct {
+	state     CircuitState
+	failures  int       // consecutive failures while closed
+	successes int       // consecutive successes while half-open
+	openedAt  time.Time // when the circuit last opened
+	trial     bool      // whether a trial task is running while half-open
+	opened    int64     // number of times the circuit opened
+	rejected  int64     // number of tasks that failed fast
+}
+
+// admission is the decision of the circuit breaker for a task.
+type admission struct {
+	b     *breaker
+	open  bool // the task fails fast
+	trial bool // the task is the trial task of a half-open circuit
+}
+
+// admit decides whether the task named name runs.
+func (gr *Group) admit(name string) admission {
+	b := gr.breaker.Load()
+	if b == nil || name == "" {
+		return admission{}
+	}
+	now := gr.getClock().Now()
+	b.mu.Lock()
+	c := b.circuits[name]
+	if c == nil {
+		b.mu.Unlock()
+		return admission{b: b}
+	}
+	from := c.state
+	if c.state == CircuitOpen && now.Sub(c.openedAt) >= b.cfg.Cooldown {
+		c.state = CircuitHalfOpen
+		c.successes = 0
+	}
+	a := admission{b: b}
+	switch {
+	case c.state == CircuitOpen, c.state == CircuitHalfOpen && c.trial:
+		a.open = true
+		c.rejected++
+	case c.state == CircuitHalfOpen:
+		a.trial = true
+		c.trial = true
+	}
+	to := c.state
+	b.mu.Unlock()
+	gr.circuitChanged(name, from, to)
+	return a
+}
+
+// record records the outcome of the task named name, which was admitted with
+// a. failed reports whether the task failed, and canceled whether the [Group]
+// was canceled.
+func (gr *Group) record(a admission, name string, failed, canceled bool) {
+	b := a.b
+	if b == nil || a.open {
+		return
+	}
+	b.mu.Lock()
+	c := b.circuits[name]
+	if c == nil {
+		c = &circuit{}
+		b.circuits[name] = c
+	}
+	if a.trial {
+		c.trial = false
+	}
+	from := c.state
+	switch {
+	case canceled:
+	case c.state == CircuitClosed && failed:
+		c.failures++
+		if c.failures >= b.cfg.Failures {
+			c.open(gr.getClock().Now())
+		}
+	case c.state == CircuitClosed:
+		c.failures = 0
+	case c.state == CircuitHalfOpen && a.trial && failed:
+		c.open(gr.getClock().Now())
+	case c.state == CircuitHalfOpen && a.trial:
+		c.successes++
+		if c.successes >= b.cfg.Successes {
+			c.state = CircuitClosed
+			c.failures = 0
+		}
+	}
+	to := c.state
+	b.mu.Unlock()
+	gr.circuitChanged(name, from, to)
+}
+
+// open opens the circuit at now.
+func (c *circuit) open(now time.Time) {
+	c.state = CircuitOpen
+	c.openedAt = now
+	c.opened++
+}
+
+// circuitChanged reports the change of the state of the circuit of name to
+// the hooks, if the state has changed.
+func (gr *Group) circuitChanged(name string, from, to CircuitState) {
+	if from == to {
+		return
+	}
+	for _, h := range gr.getHooks() {
+		if h.CircuitChanged != nil {
+			h.CircuitChanged(name, from, to)
+		}
+	}
+}
+
+// circuitOpen returns the task that replaces a task named name whose circuit
+// is open.
+func circuitOpen(name string) func(context.Context) error {
+	return func(context.Context) error {
+		return fmt.Errorf("%w: %s", ErrCircuitOpen, name)
+	}
+}
+
+// stats adds the state of the circuits to tasks. It does nothing if b is nil.
+func (b *breaker) stats(tasks map[string]TaskStats) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for name, c := range b.circuits {
+		ts := tasks[name]
+		ts.Circuit = c.state
+		ts.CircuitOpened = c.opened
+		ts.CircuitRejected = c.rejected
+		tasks[name] = ts
+	}
+}
@@ -0,0 +1,116 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/rungroup/v2/rungrouptest"
+)
+
+func TestGroup_SetCircuitBreaker(t *testing.T) {
+	ErrFail := errors.New("fail")
+	succeed := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return ErrFail }
+
+	newGroup := func(cfg *rungroup.CircuitBreaker) (*rungroup.Group, *rungrouptest.Clock, *[]string) {
+		clock := rungrouptest.NewClock(time.Unix(0, 0))
+		gr := &rungroup.Group{}
+		gr.SetClock(clock)
+		gr.SetCircuitBreaker(cfg)
+		var changes []string
+		gr.AddHooks(rungroup.Hooks{
+			CircuitChanged: func(name string, from, to rungroup.CircuitState) {
+				changes = append(changes, fmt.Sprintf("%s: %v -> %v", name, from, to))
+			},
+		})
+		return gr, clock, &changes
+	}
+	run := func(gr *rungroup.Group, name string, task func(context.Context) error) (ran bool) {
+		gr.GoNamed(name, rungroup.CancelNever, func(ctx context.Context) error {
+			ran = true
+			return task(ctx)
+		})
+		gr.Wait()
+		return ran
+	}
+
+	t.Run("open and close", func(t *testing.T) {
+		gr, clock, changes := newGroup(&rungroup.CircuitBreaker{Failures: 2, Cooldown: time.Minute, Successes: 2})
+		defer gr.Close()
+		run(gr, "db", fail)
+		run(gr, "db", succeed) // resets the count
+		run(gr, "db", fail)
+		assertEqual(t, gr.Stats().Tasks["db"].Circuit, rungroup.CircuitClosed)
+		run(gr, "db", fail)
+		assertEqual(t, gr.Stats().Tasks["db"].Circuit, rungroup.CircuitOpen)
+
+		task := gr.GoTask("db", rungroup.CancelNever, succeed)
+		assertErrorIs(t, task.Wait(), rungroup.ErrCircuitOpen)
+		assertEqual(t, run(gr, "db", succeed), false)
+		assertEqual(t, run(gr, "other", succeed), true)
+
+		clock.Advance(time.Minute)
+		assertEqual(t, run(gr, "db", succeed), true)
+		assertEqual(t, gr.Stats().Tasks["db"].Circuit, rungroup.CircuitHalfOpen)
+		assertEqual(t, run(gr, "db", succeed), true)
+
+		ts := gr.Stats().Tasks["db"]
+		assertEqual(t, ts.Circuit, rungroup.CircuitClosed)
+		assertEqual(t, ts.CircuitOpened, int64(1))
+		assertEqual(t, ts.CircuitRejected, int64(2))
+		assertEqual(t, gr.Stats().Failed, int64(5))
+		assertEqual(t, fmt.Sprint(*changes), "[db: closed -> open db: open -> half-open db: half-open -> closed]")
+	})
+
+	t.Run("trial fails", func(t *testing.T) {
+		gr, clock, changes := newGroup(&rungroup.CircuitBreaker{Failures: 1, Cooldown: time.Minute})
+		defer gr.Close()
+		run(gr, "db", fail)
+		clock.Advance(time.Minute)
+		assertEqual(t, run(gr, "db", fail), true)
+		assertEqual(t, run(gr, "db", succeed), false)
+		assertEqual(t, gr.Stats().Tasks["db"].CircuitOpened, int64(2))
+		assertEqual(t, fmt.Sprint(*changes), "[db: closed -> open db: open -> half-open db: half-open -> open]")
+	})
+
+	t.Run("one trial at a time", func(t *testing.T) {
+		gr, clock, _ := newGroup(&rungroup.CircuitBreaker{Failures: 1, Cooldown: time.Minute})
+		defer gr.Close()
+		run(gr, "db", fail)
+		clock.Advance(time.Minute)
+		release := make(chan struct{})
+		gr.GoNamed("db", rungroup.CancelNever, func(context.Context) error { <-release; return nil })
+		assertErrorIs(t, gr.GoTask("db", rungroup.CancelNever, succeed).Wait(), rungroup.ErrCircuitOpen)
+		close(release)
+		gr.Wait()
+		assertEqual(t, gr.Stats().Tasks["db"].Circuit, rungroup.CircuitClosed)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		gr, _, _ := newGroup(&rungroup.CircuitBreaker{Failures: 1})
+		defer gr.Close()
+		gr.GoNamed("db", rungroup.CancelNever, func(ctx context.Context) error {
+			gr.Close()
+			return ctx.Err()
+		})
+		gr.Wait()
+		assertEqual(t, gr.Stats().Tasks["db"].Circuit, rungroup.CircuitClosed)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		gr, _, _ := newGroup(&rungroup.CircuitBreaker{Failures: 1})
+		defer gr.Close()
+		run(gr, "db", fail)
+		gr.SetCircuitBreaker(nil)
+		assertEqual(t, run(gr, "db", succeed), true)
+	})
+}
+
+func TestCircuitState_String(t *testing.T) {
+	assertEqual(t, rungroup.CircuitHalfOpen.String(), "half-open")
+	assertEqual(t, rungroup.CircuitState(7).String(), "CircuitState(7)")
+}
@@ -0,0 +1,57 @@
+package rungroup
+
+import "time"
+
+// A Clock tells the time and creates timers for a [Group].
+//
+// The default Clock of a [Group] uses the functions of the time package.
+// Tests can replace it with [Group.SetClock] to control the passing of time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a new Timer that sends the current time on its channel
+	// after at least duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// A Timer is a single event timer created by [Clock.NewTimer].
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing. It returns false if the timer has
+	// already expired or been stopped.
+	Stop() bool
+}
+
+// SetClock sets the Clock used by the [Group] for timeouts and timestamps.
+//
+// SetClock must be called before the [Group] is used.
+func (gr *Group) SetClock(clock Clock) {
+	gr.clock.Store(&clock)
+}
+
+// getClock returns the Clock of the [Group].
+func (gr *Group) getClock() Clock {
+	if p := gr.clock.Load(); p != nil {
+		return *p
+	}
+	return systemClock{}
+}
+
+// systemClock is a Clock that uses the functions of the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer { return systemTimer{time.NewTimer(d)} }
+
+// systemTimer is a Timer that wraps a *time.Timer.
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time { return t.t.C }
+
+func (t systemTimer) Stop() bool { return t.t.Stop() }
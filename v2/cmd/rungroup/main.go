@@ -0,0 +1,78 @@
+// Command rungroup runs several shell commands concurrently in a
+// [rungroup.Group].
+//
+// Usage:
+//
+//	rungroup [flags] [command flags] command [[command flags] command ...]
+//
+// Each command is run with the shell, and the flags in front of it choose how
+// its exit affects the other commands. They map to the methods of
+// [rungroup.Group] that start a task:
+//
+//	-go                 Group.Go: never cancel the others (the default)
+//	-cancel-on-finish   Group.GoCancelOnFinish: cancel the others when it exits
+//	-cancel-on-success  Group.GoCancelOnSuccess: cancel the others when it succeeds
+//	-cancel-on-error    Group.GoCancelOnError: cancel the others when it fails
+//	-name NAME          the name of the task, instead of its index
+//
+// The global flags may appear in front of any command:
+//
+//	-timeout DURATION       cancel the group after DURATION (Group.SetTimeout)
+//	-kill-timeout DURATION  how long a command has to exit after SIGTERM before
+//	                        it is killed with SIGKILL (default 5s)
+//	-raw                    write the output of the commands unmodified
+//	-timestamps             prefix each line of output with the time
+//	-color WHEN             color the prefixes: auto (the default), always or never
+//	-log-dir DIR            also write the output of each command to DIR/NAME.log
+//
+// The output of the commands is written a line at a time, so that the lines of
+// different commands never interleave, and each line is prefixed with the name
+// of the command that wrote it. The -raw flag passes the output through as is
+// instead.
+//
+// When the group is canceled, every running command receives SIGTERM, and
+// SIGKILL if it is still running after the kill timeout. An interrupt or
+// SIGTERM sent to rungroup cancels the group.
+//
+// The procfile subcommand runs the processes of a Procfile instead:
+//
+//	rungroup procfile [-timeout DURATION] [-kill-timeout DURATION] [-env FILE] [-restart] [file]
+//
+// Each line of the Procfile, which defaults to "Procfile", is a process in the
+// form "name: command", which is run as a task with that name. The variables
+// in the environment file, which defaults to ".env" if it exists, are added
+// to the environment of the processes. A process may end with annotations in
+// a comment that starts with "# rungroup:", which other Procfile runners
+// ignore:
+//
+//	web: ./server -port 8080
+//	test: go test ./... # rungroup: cancel-on-finish
+//	worker: ./worker # rungroup: cancel-on-error restart
+//
+// The annotations are the policies named after the flags above, and
+// "restart", which restarts the process a second after it fails, until the
+// group is canceled. The -restart flag applies "restart" to every process. A
+// process that is restarted never fails, so its failures never cancel the
+// group.
+//
+// At the end, rungroup prints what canceled the group, if anything: a signal,
+// the timeout, or the command that finished or failed. The exit status
+// reflects the cause of the cancellation of the group: 0 if
+// the group was not canceled, or was canceled by a signal or by a command
+// that finished successfully; 124 if the timeout expired; the exit status of
+// the command that canceled the group if it failed; and 1 otherwise. Usage
+// errors exit with 2.
+//
+// For example, the following runs the tests against a server, and stops the
+// server once the tests have finished:
+//
+//	rungroup -timeout 10m -name server 'make serve' -cancel-on-finish -name test 'make test'
+package main
+
+import (
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// colors are the ANSI colors of the prefixes, used in turn.
+var colors = []string{"36", "33", "32", "35", "34", "31"}
+
+// maxLine is the length at which a line without a newline is written anyway.
+const maxLine = 64 << 10
+
+// output multiplexes the output of the commands onto the output of rungroup.
+//
+// Unless the -raw flag is set, the output of each command is written a line
+// at a time, prefixed with the name of the command, so that the lines of
+// different commands never interleave.
+type output struct {
+	mu         sync.Mutex
+	stdout     io.Writer
+	stderr     io.Writer
+	timestamps bool
+	now        func() time.Time
+	tasks      []*taskOutput
+	logs       []*os.File
+}
+
+// newOutput returns the output for cmds, which writes to stdout and stderr,
+// and colors the prefixes if color is true.
+func newOutput(opts options, cmds []command, stdout, stderr io.Writer, color bool) (*output, error) {
+	o := &output{stdout: stdout, stderr: stderr, timestamps: opts.timestamps, now: time.Now}
+	width := 0
+	for _, c := range cmds {
+		if len(c.name) > width {
+			width = len(c.name)
+		}
+	}
+	for i, c := range cmds {
+		var log io.Writer
+		if opts.logDir != "" {
+			f, err := o.openLog(opts.logDir, c.name)
+			if err != nil {
+				o.Close()
+				return nil, err
+			}
+			log = f
+		}
+		if opts.raw {
+			t := &taskOutput{Stdout: stdout, Stderr: stderr}
+			if log != nil {
+				t.Stdout = io.MultiWriter(stdout, log)
+				t.Stderr = io.MultiWriter(stderr, log)
+			}
+			o.tasks = append(o.tasks, t)
+			continue
+		}
+		prefix := fmt.Sprintf("%-*s | ", width, c.name)
+		if color {
+			prefix = "\x1b[" + colors[i%len(colors)] + "m" + prefix + "\x1b[0m"
+		}
+		o.tasks = append(o.tasks, &taskOutput{
+			Stdout: &lineWriter{out: o, dst: stdout, prefix: prefix, log: log},
+			Stderr: &lineWriter{out: o, dst: stderr, prefix: prefix, log: log},
+		})
+	}
+	return o, nil
+}
+
+// openLog creates the log file of the command name in dir.
+func (o *output) openLog(dir, name string) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(filepath.Join(dir, name+".log"))
+	if err != nil {
+		return nil, err
+	}
+	o.logs = append(o.logs, f)
+	return f, nil
+}
+
+// Printf writes a message of rungroup itself to stderr.
+func (o *output) Printf(format string, a ...any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fmt.Fprintf(o.stderr, format, a...)
+}
+
+// Close closes the log files.
+func (o *output) Close() error {
+	var first error
+	for _, f := range o.logs {
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// writeLine writes a line of the output of a command, followed by a newline.
+func (o *output) writeLine(w *lineWriter, line []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var ts string
+	if o.timestamps {
+		ts = o.now().Format("15:04:05.000 ")
+	}
+	io.WriteString(w.dst, ts+w.prefix+string(line)+"\n")
+	if w.log != nil {
+		io.WriteString(w.log, ts+string(line)+"\n")
+	}
+}
+
+// taskOutput is where a command writes its output.
+type taskOutput struct {
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Flush writes the last line of the output, if it does not end with a
+// newline.
+func (t *taskOutput) Flush() {
+	for _, w := range []io.Writer{t.Stdout, t.Stderr} {
+		if lw, ok := w.(*lineWriter); ok {
+			lw.Flush()
+		}
+	}
+}
+
+// lineWriter buffers the output written to it, and passes it on a line at a
+// time.
+//
+// A lineWriter is used by one goroutine at a time.
+type lineWriter struct {
+	out    *output
+	dst    io.Writer
+	prefix string
+	log    io.Writer
+	buf    []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i, next := bytes.IndexByte(w.buf, '\n'), 1
+		if i == -1 {
+			if len(w.buf) < maxLine {
+				break
+			}
+			i, next = maxLine, 0
+		}
+		w.out.writeLine(w, w.buf[:i])
+		w.buf = w.buf[i+next:]
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Flush writes the buffered partial line.
+func (w *lineWriter) Flush() {
+	if len(w.buf) != 0 {
+		w.out.writeLine(w, w.buf)
+		w.buf = nil
+	}
+}
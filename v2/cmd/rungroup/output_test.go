@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOutput(t *testing.T) {
+	cmds := []command{{name: "web"}, {name: "worker"}}
+
+	t.Run("prefixed", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		out, err := newOutput(defaultOptions(), cmds, &stdout, &stderr, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer out.Close()
+		web, worker := out.tasks[0], out.tasks[1]
+		fmt.Fprint(web.Stdout, "hel")
+		fmt.Fprint(worker.Stdout, "one\ntw")
+		fmt.Fprint(web.Stdout, "lo\npartial")
+		fmt.Fprint(worker.Stderr, "error\n")
+		web.Flush()
+		worker.Flush()
+		out.Printf("rungroup: done\n")
+		assertOutput(t, stdout.String(), "worker | one\nweb    | hello\nweb    | partial\nworker | tw\n")
+		assertOutput(t, stderr.String(), "worker | error\nrungroup: done\n")
+	})
+
+	t.Run("colored with timestamps", func(t *testing.T) {
+		var stdout bytes.Buffer
+		opts := defaultOptions()
+		opts.timestamps = true
+		out, err := newOutput(opts, cmds, &stdout, &stdout, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer out.Close()
+		out.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC) }
+		fmt.Fprint(out.tasks[1].Stdout, "x\n")
+		assertOutput(t, stdout.String(), "03:04:05.006 \x1b[33mworker | \x1b[0mx\n")
+	})
+
+	t.Run("long line", func(t *testing.T) {
+		var stdout bytes.Buffer
+		out, err := newOutput(defaultOptions(), cmds[:1], &stdout, &stdout, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer out.Close()
+		fmt.Fprint(out.tasks[0].Stdout, strings.Repeat("x", maxLine+1))
+		out.tasks[0].Flush()
+		assertOutput(t, stdout.String(), "web | "+strings.Repeat("x", maxLine)+"\nweb | x\n")
+	})
+
+	for _, raw := range []bool{false, true} {
+		t.Run(fmt.Sprintf("log files raw=%v", raw), func(t *testing.T) {
+			var stdout bytes.Buffer
+			opts := defaultOptions()
+			opts.raw = raw
+			opts.logDir = filepath.Join(t.TempDir(), "logs")
+			out, err := newOutput(opts, cmds, &stdout, &stdout, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fmt.Fprint(out.tasks[0].Stdout, "out\n")
+			fmt.Fprint(out.tasks[0].Stderr, "err\n")
+			if err := out.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if raw {
+				assertOutput(t, stdout.String(), "out\nerr\n")
+			}
+			b, err := os.ReadFile(filepath.Join(opts.logDir, "web.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertOutput(t, string(b), "out\nerr\n")
+		})
+	}
+}
+
+func assertOutput(t *testing.T, actual, expect string) {
+	t.Helper()
+	if actual != expect {
+		t.Errorf("output=%q, expect=%q", actual, expect)
+	}
+}
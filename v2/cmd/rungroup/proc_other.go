@@ -0,0 +1,22 @@
+//go:build !unix
+
+package main
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// shell returns the command that runs line with the shell.
+func shell(ctx context.Context, line string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/C", line)
+}
+
+// terminate arranges for cmd to be killed when the context of cmd is done.
+//
+// There is no SIGTERM on this platform, so the process is killed immediately.
+func terminate(cmd *exec.Cmd, killTimeout time.Duration) (stop func()) {
+	cmd.WaitDelay = killTimeout
+	return func() {}
+}
@@ -0,0 +1,40 @@
+//go:build unix
+
+package main
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// shell returns the command that runs line with the shell.
+func shell(ctx context.Context, line string) *exec.Cmd {
+	return exec.CommandContext(ctx, "/bin/sh", "-c", line)
+}
+
+// terminate arranges for the process group of cmd to receive SIGTERM when the
+// context of cmd is done, and SIGKILL if it is still running after
+// killTimeout. After killTimeout, cmd also stops waiting for the output of
+// the processes that are still running. The returned function must be called
+// after cmd has exited.
+func terminate(cmd *exec.Cmd, killTimeout time.Duration) (stop func()) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.WaitDelay = killTimeout
+	done := make(chan struct{})
+	cmd.Cancel = func() error {
+		pgid := -cmd.Process.Pid
+		go func() {
+			timer := time.NewTimer(killTimeout)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				syscall.Kill(pgid, syscall.SIGKILL)
+			case <-done:
+			}
+		}()
+		return syscall.Kill(pgid, syscall.SIGTERM)
+	}
+	return func() { close(done) }
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+// policyNames maps the names of the policies in the annotations of a Procfile
+// to the policies.
+var policyNames = map[string]rungroup.Policy{
+	"go":                rungroup.CancelNever,
+	"cancel-on-finish":  rungroup.CancelOnFinish,
+	"cancel-on-success": rungroup.CancelOnSuccess,
+	"cancel-on-error":   rungroup.CancelOnError,
+}
+
+// annotationMarker starts the annotations at the end of a line of a Procfile.
+//
+// The annotations are a comment for the shell, so other Procfile runners
+// ignore them.
+const annotationMarker = "# rungroup:"
+
+// procfileLine matches a process of a Procfile.
+var procfileLine = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.+)$`)
+
+// parseProcfileArgs parses the command line of the procfile subcommand, and
+// reads the Procfile and the environment file.
+func parseProcfileArgs(args []string) (options, []command, error) {
+	opts := defaultOptions()
+	fs := flag.NewFlagSet("rungroup procfile", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: rungroup procfile [flags] [file]")
+		fs.PrintDefaults()
+	}
+	opts.flags(fs)
+	envFile := fs.String("env", "", "read the environment from `file` (default \".env\" if it exists)")
+	restart := fs.Bool("restart", false, "restart every process when it fails")
+	if err := fs.Parse(args); err != nil {
+		return opts, nil, errUsage
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return opts, nil, errUsage
+	}
+	name := "Procfile"
+	if fs.NArg() == 1 {
+		name = fs.Arg(0)
+	}
+
+	env, err := readEnvFile(*envFile)
+	if err != nil {
+		return opts, nil, err
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return opts, nil, err
+	}
+	defer f.Close()
+	cmds, err := parseProcfile(f)
+	if err != nil {
+		return opts, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(cmds) == 0 {
+		return opts, nil, fmt.Errorf("%s: no processes", name)
+	}
+	for i := range cmds {
+		cmds[i].env = env
+		cmds[i].restart = cmds[i].restart || *restart
+	}
+	return opts, cmds, nil
+}
+
+// parseProcfile parses a Procfile, which has a process on each line in the
+// form "name: command".
+//
+// A process may end with annotations, which are a comment starting with
+// "# rungroup:" followed by words separated by spaces or commas: a policy
+// from [policyNames], or "restart" to restart the process when it fails.
+// Blank lines and lines starting with "#" are ignored.
+func parseProcfile(r io.Reader) ([]command, error) {
+	var cmds []command
+	seen := map[string]bool{}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m := procfileLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("line %d: not in the form \"name: command\"", n)
+		}
+		c := command{name: m[1], line: m[2]}
+		if seen[c.name] {
+			return nil, fmt.Errorf("line %d: duplicate process %s", n, c.name)
+		}
+		seen[c.name] = true
+		if i := strings.LastIndex(c.line, annotationMarker); i != -1 {
+			annotations := c.line[i+len(annotationMarker):]
+			c.line = strings.TrimSpace(c.line[:i])
+			if err := c.annotate(annotations); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+		}
+		cmds = append(cmds, c)
+	}
+	return cmds, sc.Err()
+}
+
+// annotate applies the annotations of a process of a Procfile to c.
+func (c *command) annotate(annotations string) error {
+	policies := 0
+	for _, word := range strings.FieldsFunc(annotations, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}) {
+		if word == "restart" {
+			c.restart = true
+			continue
+		}
+		policy, ok := policyNames[word]
+		if !ok {
+			return fmt.Errorf("unknown annotation %q", word)
+		}
+		c.policy = policy
+		policies++
+	}
+	if policies > 1 {
+		return errors.New("more than one policy")
+	}
+	return nil
+}
+
+// readEnvFile reads the environment file name, and returns its variables in
+// the form "key=value".
+//
+// If name is empty, readEnvFile reads ".env" if it exists.
+func readEnvFile(name string) ([]string, error) {
+	optional := name == ""
+	if optional {
+		name = ".env"
+	}
+	f, err := os.Open(name)
+	if optional && errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	env, err := parseEnv(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return env, nil
+}
+
+// parseEnv parses an environment file, which has a variable on each line in
+// the form "key=value", optionally preceded by "export".
+//
+// A value may be quoted with single quotes, which are removed, or with double
+// quotes, which are unquoted as a Go string. Blank lines and lines starting
+// with "#" are ignored.
+func parseEnv(r io.Reader) ([]string, error) {
+	var env []string
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: not in the form \"key=value\"", n)
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			v, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			value = v
+		}
+		env = append(env, key+"="+value)
+	}
+	return env, sc.Err()
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestParseProcfile(t *testing.T) {
+	cmds, err := parseProcfile(strings.NewReader(`
+# comment
+web: ./server -port 8080
+test: go test ./... # rungroup: cancel-on-finish
+worker_1: ./worker # rungroup: cancel-on-error, restart
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []command{
+		{name: "web", policy: rungroup.CancelNever, line: "./server -port 8080"},
+		{name: "test", policy: rungroup.CancelOnFinish, line: "go test ./..."},
+		{name: "worker_1", policy: rungroup.CancelOnError, line: "./worker", restart: true},
+	}
+	if !reflect.DeepEqual(cmds, expect) {
+		t.Errorf("commands=%+v, expect=%+v", cmds, expect)
+	}
+
+	for _, procfile := range []string{
+		"web ./server",
+		"web: a\nweb: b",
+		"web: a # rungroup: sometimes",
+		"web: a # rungroup: go cancel-on-error",
+	} {
+		if _, err := parseProcfile(strings.NewReader(procfile)); err == nil {
+			t.Errorf("parseProcfile(%q) succeeded", procfile)
+		}
+	}
+}
+
+func TestParseEnv(t *testing.T) {
+	env, err := parseEnv(strings.NewReader(`
+# comment
+A=1
+export B = two words
+C='single $quoted'
+D="double\tquoted"
+E=
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"A=1", "B=two words", "C=single $quoted", "D=double\tquoted", "E="}
+	if !reflect.DeepEqual(env, expect) {
+		t.Errorf("env=%q, expect=%q", env, expect)
+	}
+
+	for _, file := range []string{"A", "=1", "A B=1", `A="unterminated\"`} {
+		if _, err := parseEnv(strings.NewReader(file)); err == nil {
+			t.Errorf("parseEnv(%q) succeeded", file)
+		}
+	}
+}
+
+func TestRun_procfile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands are written for /bin/sh")
+	}
+	delay := restartDelay
+	restartDelay = 10 * time.Millisecond
+	defer func() { restartDelay = delay }()
+	stderr := os.Stderr
+	os.Stderr, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	defer func() { os.Stderr.Close(); os.Stderr = stderr }()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		name = filepath.Join(dir, name)
+		if err := os.WriteFile(name, []byte(content), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+	env := write("env", "GREETING=hello\n")
+	counter := filepath.Join(dir, "counter")
+	procfile := write("Procfile", `
+greet: test "$GREETING" = hello || exit 5 # rungroup: cancel-on-error
+flaky: echo >> `+counter+`; test $(wc -l < `+counter+`) -ge 3 # rungroup: restart, cancel-on-success
+server: sleep 10
+`)
+	status := run([]string{"procfile", "-env", env, procfile})
+	if status != 0 {
+		t.Errorf("status=%d, expect=0", status)
+	}
+	if b, _ := os.ReadFile(counter); strings.Count(string(b), "\n") != 3 {
+		t.Errorf("flaky ran %d times, expect 3", strings.Count(string(b), "\n"))
+	}
+
+	if status := run([]string{"procfile", filepath.Join(dir, "missing")}); status != 2 {
+		t.Errorf("status=%d, expect=2", status)
+	}
+}
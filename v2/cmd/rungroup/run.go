@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+// options are the global flags.
+type options struct {
+	timeout     time.Duration
+	killTimeout time.Duration
+	raw         bool   // write the output of the commands unmodified
+	timestamps  bool   // prefix each line with the time
+	color       string // "auto", "always" or "never"
+	logDir      string // write the output of each command to a file in logDir
+}
+
+// defaultOptions returns the default values of the global flags.
+func defaultOptions() options {
+	return options{killTimeout: 5 * time.Second, color: "auto"}
+}
+
+// flags defines the global flags in fs.
+func (opts *options) flags(fs *flag.FlagSet) {
+	fs.DurationVar(&opts.timeout, "timeout", opts.timeout, "cancel the group after `duration`")
+	fs.DurationVar(&opts.killTimeout, "kill-timeout", opts.killTimeout, "how long a command has to exit after SIGTERM before it is killed")
+	fs.BoolVar(&opts.raw, "raw", opts.raw, "write the output of the commands unmodified, without prefixes")
+	fs.BoolVar(&opts.timestamps, "timestamps", opts.timestamps, "prefix each line of output with the time")
+	fs.StringVar(&opts.color, "color", opts.color, "color the prefixes: `when` is auto, always or never")
+	fs.StringVar(&opts.logDir, "log-dir", opts.logDir, "also write the output of each command to `dir`/NAME.log")
+}
+
+// validate reports an error if the global flags are invalid.
+func (opts *options) validate() error {
+	switch opts.color {
+	case "auto", "always", "never":
+		return nil
+	}
+	return fmt.Errorf("invalid value %q for -color", opts.color)
+}
+
+// command is a command to run, with the flags in front of it.
+type command struct {
+	name    string
+	policy  rungroup.Policy
+	line    string
+	env     []string // added to the environment of rungroup
+	restart bool     // restart the command when it fails
+}
+
+// errUsage is returned by parseArgs after the usage has been printed.
+var errUsage = errors.New("usage")
+
+// restartDelay is how long a command that failed waits before it is
+// restarted.
+var restartDelay = time.Second
+
+// run runs the rungroup command with args, and returns its exit status.
+func run(args []string) int {
+	parse := parseArgs
+	if len(args) != 0 && args[0] == "procfile" {
+		parse, args = parseProcfileArgs, args[1:]
+	}
+	opts, cmds, err := parse(args)
+	if err == nil {
+		err = opts.validate()
+	}
+	if err != nil {
+		if err != errUsage {
+			fmt.Fprintln(os.Stderr, "rungroup:", err)
+		}
+		return 2
+	}
+	return runCommands(opts, cmds)
+}
+
+// runCommands runs cmds in a group, prints the cause of the cancellation of
+// the group, and returns the exit status.
+func runCommands(opts options, cmds []command) int {
+	out, err := newOutput(opts, cmds, os.Stdout, os.Stderr, useColor(opts.color, os.Stdout))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "rungroup:", err)
+		return 1
+	}
+	defer out.Close()
+
+	gr := rungroup.New(context.Background())
+	defer gr.Close()
+	gr.EnableReport()
+	if opts.timeout > 0 {
+		gr.SetTimeout(opts.timeout)
+	}
+	stop := cancelOnSignal(gr)
+	defer stop()
+	for i, c := range cmds {
+		c, w := c, out.tasks[i]
+		gr.GoNamed(c.name, c.policy, func(ctx context.Context) error {
+			defer w.Flush()
+			return c.run(ctx, opts, w)
+		})
+	}
+	gr.Wait()
+	status, summary := exitStatus(gr, opts)
+	if summary != "" {
+		out.Printf("rungroup: %s\n", summary)
+	}
+	return status
+}
+
+// cancelOnSignal cancels gr with a cause that matches [rungroup.ErrSignal]
+// when rungroup receives an interrupt or SIGTERM. A second signal terminates
+// rungroup.
+//
+// The returned function stops handling the signals.
+func cancelOnSignal(gr *rungroup.Group) (stop func()) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case s := <-sig:
+			signal.Stop(sig)
+			gr.Cancel(fmt.Errorf("%w: %v", rungroup.ErrSignal, s))
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(sig)
+		close(done)
+	}
+}
+
+// parseArgs parses the command line into the global flags and the commands.
+func parseArgs(args []string) (options, []command, error) {
+	opts := defaultOptions()
+	var cmds []command
+	for {
+		fs := flag.NewFlagSet("rungroup", flag.ContinueOnError)
+		fs.Usage = func() {
+			fmt.Fprintln(fs.Output(), "usage: rungroup [flags] [command flags] command [[command flags] command ...]")
+			fmt.Fprintln(fs.Output(), "       rungroup procfile [flags] [file]")
+			fs.PrintDefaults()
+		}
+		opts.flags(fs)
+		var c command
+		fs.StringVar(&c.name, "name", "", "the `name` of the next command, instead of its index")
+		policies := map[rungroup.Policy]*bool{
+			rungroup.CancelNever:     fs.Bool("go", false, "never cancel the group when the next command exits (the default)"),
+			rungroup.CancelOnFinish:  fs.Bool("cancel-on-finish", false, "cancel the group when the next command exits"),
+			rungroup.CancelOnSuccess: fs.Bool("cancel-on-success", false, "cancel the group when the next command succeeds"),
+			rungroup.CancelOnError:   fs.Bool("cancel-on-error", false, "cancel the group when the next command fails"),
+		}
+		if err := fs.Parse(args); err != nil {
+			return opts, nil, errUsage
+		}
+		n := 0
+		for policy, set := range policies {
+			if *set {
+				c.policy = policy
+				n++
+			}
+		}
+		if c.name == "" {
+			c.name = strconv.Itoa(len(cmds))
+		}
+		if n > 1 {
+			return opts, nil, fmt.Errorf("more than one policy for command %s", c.name)
+		}
+		if fs.NArg() == 0 {
+			if len(cmds) == 0 {
+				fs.Usage()
+				return opts, nil, errUsage
+			}
+			if fs.NFlag() != 0 {
+				return opts, nil, errors.New("flags after the last command")
+			}
+			return opts, cmds, nil
+		}
+		c.line = fs.Arg(0)
+		cmds = append(cmds, c)
+		args = fs.Args()[1:]
+	}
+}
+
+// run runs the command until it exits or ctx is done, and writes its output to
+// w.
+//
+// If c.restart is true, run restarts the command after [restartDelay] each
+// time it fails, until ctx is done.
+func (c command) run(ctx context.Context, opts options, w *taskOutput) error {
+	for {
+		err := c.runOnce(ctx, opts, w)
+		if err == nil || !c.restart || ctx.Err() != nil {
+			return err
+		}
+		fmt.Fprintf(w.Stderr, "rungroup: %v; restarting in %v\n", err, restartDelay)
+		timer := time.NewTimer(restartDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// runOnce runs the command once, until it exits or ctx is done.
+func (c command) runOnce(ctx context.Context, opts options, w *taskOutput) error {
+	cmd := shell(ctx, c.line)
+	if c.env != nil {
+		cmd.Env = append(os.Environ(), c.env...)
+	}
+	cmd.Stdout = w.Stdout
+	cmd.Stderr = w.Stderr
+	stop := terminate(cmd, opts.killTimeout)
+	defer stop()
+	return cmd.Run()
+}
+
+// exitStatus returns the exit status of rungroup, and a summary of the cause
+// of the cancellation of gr, which is empty if gr was not canceled.
+//
+// The exit status is 0 if gr was not canceled, or was canceled by a signal or
+// by a command that finished successfully; [rungroup.TimeoutExitCode] if the
+// timeout expired; the exit status of the command that canceled gr if it
+// failed; and 1 otherwise.
+func exitStatus(gr *rungroup.Group, opts options) (int, string) {
+	var cause error
+	for _, a := range gr.CancelHistory() {
+		if a.First {
+			cause = a.Cause
+		}
+	}
+	switch {
+	case cause == nil:
+		return 0, ""
+	case errors.Is(cause, rungroup.ErrSignal):
+		return 0, "canceled by " + errors.Unwrap(cause).Error()
+	case errors.Is(cause, context.DeadlineExceeded):
+		return rungroup.TimeoutExitCode, fmt.Sprintf("canceled by the timeout of %v", opts.timeout)
+	}
+	name := "?"
+	for _, t := range gr.Report().Tasks {
+		if t.Triggered {
+			name = t.Name
+		}
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(cause, context.Canceled):
+		return 0, fmt.Sprintf("canceled by %s, which finished successfully", name)
+	case errors.As(cause, &exitErr) && exitErr.ExitCode() > 0:
+		return exitErr.ExitCode(), fmt.Sprintf("canceled by %s, which failed: %v", name, exitErr)
+	default:
+		if err := errors.Unwrap(cause); err != nil {
+			cause = err
+		}
+		return 1, fmt.Sprintf("canceled by %s, which failed: %v", name, cause)
+	}
+}
+
+// useColor reports whether to color the output written to w, according to the
+// -color flag.
+func useColor(when string, w io.Writer) bool {
+	switch when {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
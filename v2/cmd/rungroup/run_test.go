@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestParseArgs(t *testing.T) {
+	opts, cmds, err := parseArgs([]string{
+		"-timeout", "1m", "-name", "server", "serve",
+		"-cancel-on-finish", "test",
+		"-kill-timeout=1s", "-cancel-on-error", "lint",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.timeout != time.Minute || opts.killTimeout != time.Second {
+		t.Errorf("options=%+v", opts)
+	}
+	expect := []command{
+		{name: "server", policy: rungroup.CancelNever, line: "serve"},
+		{name: "1", policy: rungroup.CancelOnFinish, line: "test"},
+		{name: "2", policy: rungroup.CancelOnError, line: "lint"},
+	}
+	if !reflect.DeepEqual(cmds, expect) {
+		t.Errorf("commands=%+v, expect=%+v", cmds, expect)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"-go", "-cancel-on-error", "x"},
+		{"x", "-name", "y"},
+		{"-unknown", "x"},
+	} {
+		if _, _, err := parseArgs(args); err == nil {
+			t.Errorf("parseArgs(%q) succeeded", args)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands are written for /bin/sh")
+	}
+	tests := []struct {
+		name   string
+		args   []string
+		status int
+	}{
+		{"all succeed", []string{"true", "true"}, 0},
+		{"failure ignored", []string{"exit 3", "true"}, 0},
+		{"canceled on error", []string{"-cancel-on-error", "exit 3", "sleep 10"}, 3},
+		{"canceled on finish", []string{"-cancel-on-finish", "sleep 0.1", "sleep 10"}, 0},
+		{"terminated", []string{"-kill-timeout", "100ms", "-cancel-on-finish", "sleep 0.1", "trap '' TERM; sleep 10"}, 0},
+		{"timeout", []string{"-timeout", "100ms", "sleep 10"}, rungroup.TimeoutExitCode},
+		{"usage", []string{"-unknown"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stderr := os.Stderr
+			os.Stderr, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+			defer func() { os.Stderr.Close(); os.Stderr = stderr }()
+			start := time.Now()
+			status := run(tt.args)
+			if status != tt.status {
+				t.Errorf("status=%d, expect=%d", status, tt.status)
+			}
+			if d := time.Since(start); d > 5*time.Second {
+				t.Errorf("took %v", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package rungroup
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+)
+
+// A TaskID identifies a task started in a [Group].
+//
+// Task IDs are unique within a process, so they can be used to correlate log
+// entries of a task across groups.
+type TaskID uint64
+
+// String returns the decimal representation of id.
+func (id TaskID) String() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// lastTaskID is the most recently assigned TaskID.
+var lastTaskID atomic.Uint64
+
+// taskKey is the context key for the task running in a [Group].
+type taskKey struct{}
+
+// taskContext is the context passed to a task. It carries the identity of
+// the task under taskKey, which saves an allocation over context.WithValue.
+type taskContext struct {
+	context.Context
+	group *Group
+	id    TaskID
+}
+
+func (c *taskContext) Value(key any) any {
+	if key == (taskKey{}) {
+		return c
+	}
+	return c.Context.Value(key)
+}
+
+// withTask returns a copy of the group's context ctx that carries the identity
+// of a new task of gr, along with the ID of that task.
+func withTask(ctx context.Context, gr *Group) (context.Context, TaskID) {
+	id := TaskID(lastTaskID.Add(1))
+	return &taskContext{Context: ctx, group: gr, id: id}, id
+}
+
+// FromContext returns the [Group] that owns the task running with ctx, and the
+// ID of that task.
+//
+// The context passed to a task, and any context derived from it, carries the
+// task's identity. If ctx does not, FromContext returns ok as false.
+//
+// If tasks of several groups are nested, FromContext returns the innermost
+// [Group], i.e. the one that started the task whose context ctx derives from.
+//
+// Use Cases:
+//
+// Use this when code deep in a call chain needs to start sibling tasks, or to
+// cancel the [Group], without having the *Group passed down to it explicitly.
+func FromContext(ctx context.Context) (gr *Group, id TaskID, ok bool) {
+	v, ok := ctx.Value(taskKey{}).(*taskContext)
+	if !ok {
+		return nil, 0, false
+	}
+	return v.group, v.id, true
+}
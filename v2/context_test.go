@@ -0,0 +1,74 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func ExampleFromContext() {
+	var gr rungroup.Group
+	defer gr.Close()
+	gr.Go(func(ctx context.Context) {
+		spawnSibling(ctx)
+	})
+	fmt.Println(gr.Wait())
+	// Output:
+	// sibling done (context_test.go:30 spawnSibling.func1)
+}
+
+// spawnSibling starts a sibling task in the group of the task running with ctx.
+func spawnSibling(ctx context.Context) {
+	gr, _, ok := rungroup.FromContext(ctx)
+	if !ok {
+		return
+	}
+	gr.Go(func(ctx context.Context) {
+		gr.Cancel(errors.New("sibling done"))
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("not a task", func(t *testing.T) {
+		gr, id, ok := rungroup.FromContext(context.TODO())
+		assertEqual(t, gr, nil)
+		assertEqual(t, id, 0)
+		assertEqual(t, ok, false)
+	})
+
+	t.Run("task", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		ids := make(chan rungroup.TaskID, 2)
+		for i := 0; i < 2; i++ {
+			gr.Go(func(ctx context.Context) {
+				owner, id, ok := rungroup.FromContext(ctx)
+				assertEqual(t, owner, &gr)
+				assertEqual(t, ok, true)
+				ids <- id
+			})
+		}
+		assertNoError(t, gr.Wait())
+		a, b := <-ids, <-ids
+		assertEqual(t, a != b, true, "task IDs must be unique")
+		assertEqual(t, a != 0 && b != 0, true, "task IDs must not be zero")
+	})
+
+	t.Run("nested groups", func(t *testing.T) {
+		var outer rungroup.Group
+		defer outer.Close()
+		outer.Go(func(ctx context.Context) {
+			inner := rungroup.New(ctx)
+			defer inner.Close()
+			inner.Go(func(ctx context.Context) {
+				owner, _, _ := rungroup.FromContext(ctx)
+				assertEqual(t, owner, inner)
+			})
+			inner.Wait()
+		})
+		assertNoError(t, outer.Wait())
+	})
+}
@@ -0,0 +1,89 @@
+package rungroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/goaux/stacktrace/v2"
+)
+
+// ErrDraining is reported for the tasks that are not started because the
+// [Group] is draining. See [Group.Drain].
+var ErrDraining = errors.New("draining")
+
+// ErrDrainTimeout is matched, with [errors.Is], by the cause with which
+// [Group.Drain] cancels the [Group] when its context is done before all tasks
+// have finished.
+var ErrDrainTimeout = errors.New("drain timeout")
+
+// Drain stops the [Group] from starting new tasks, waits for the running tasks
+// to finish, and then cancels the [Group] with [ErrClosed], as [Group.Close]
+// does.
+//
+// Once Drain has been called, the methods that start a task do not start it:
+// [Group.TryGo] returns false, the [Task] returned by [Group.GoTask] finishes
+// immediately with [ErrDraining], and the other methods return without doing
+// anything. Rejected tasks are counted in [Stats].Rejected and reported to
+// [Hooks].TaskRejected. This applies to tasks started by the running tasks as
+// well, and to the tasks that were waiting for a limit, such as the one set by
+// [Group.SetLimit], when Drain was called: they are rejected once the limit
+// lets them through.
+//
+// The running tasks keep their context intact while they finish. If ctx is
+// done before they have all finished, Drain cancels the [Group] with a cause
+// that matches both [ErrDrainTimeout] and the cause of ctx, and returns that
+// cause without waiting for the tasks to return. Otherwise Drain returns nil.
+//
+// Use Cases:
+//
+// Use this for a graceful shutdown, where the work in progress should be
+// completed but no new work should be accepted.
+func (gr *Group) Drain(ctx context.Context) error {
+	callers := gr.callers(1)
+	gr.getContext()
+	gr.drainMu.Lock()
+	gr.draining.Store(true)
+	gr.drainMu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		gr.g.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		gr.cancelCause(stacktrace.NewError(ErrClosed, callers))
+		return nil
+	case <-ctx.Done():
+		cause := stacktrace.NewError(fmt.Errorf("%w: %w", ErrDrainTimeout, context.Cause(ctx)), callers)
+		gr.cancelCause(cause)
+		return cause
+	}
+}
+
+// enter counts a task that is about to be started in gr.g before it waits for
+// the limits of the [Group], so that [Group.Drain] waits for it as well. It
+// reports false, without counting the task, if the [Group] is draining.
+func (gr *Group) enter() bool {
+	gr.drainMu.RLock()
+	defer gr.drainMu.RUnlock()
+	if gr.draining.Load() {
+		return false
+	}
+	gr.g.Add(1)
+	return true
+}
+
+// reject reports a task that is not started because the [Group] is draining.
+// handle is the handle of the task, or nil.
+func (gr *Group) reject(handle *Task) {
+	gr.stats.rejected.Add(1)
+	for _, h := range gr.getHooks() {
+		if h.TaskRejected != nil {
+			h.TaskRejected(ErrDraining)
+		}
+	}
+	if handle != nil {
+		handle.finish(ErrDraining)
+	}
+}
@@ -0,0 +1,80 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestGroup_Drain(t *testing.T) {
+	t.Run("drained", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		rejected := 0
+		gr.AddHooks(rungroup.Hooks{TaskRejected: func(err error) {
+			assertErrorIs(t, err, rungroup.ErrDraining)
+			rejected++
+		}})
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var canceledWhileRunning bool
+		gr.Go(func(ctx context.Context) {
+			close(started)
+			<-release
+			canceledWhileRunning = ctx.Err() != nil
+		})
+		<-started
+		drained := make(chan error)
+		go func() { drained <- gr.Drain(context.Background()) }()
+		for gr.TryGo(func(context.Context) {}) {
+			// until Drain has started
+		}
+		gr.Go(func(context.Context) { t.Error("must not run") })
+		task := gr.GoTask("", rungroup.CancelNever, func(context.Context) error { return nil })
+		assertErrorIs(t, task.Wait(), rungroup.ErrDraining)
+		close(release)
+		assertNoError(t, <-drained)
+		assertErrorIs(t, gr.Wait(), rungroup.ErrClosed)
+		assertEqual(t, canceledWhileRunning, false)
+		assertEqual(t, rejected, 3)
+		assertEqual(t, gr.Stats().Rejected, 3)
+	})
+
+	t.Run("waiting for the limit", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetLimit(1)
+		release := make(chan struct{})
+		gr.Go(func(context.Context) { <-release })
+		waiting := make(chan struct{})
+		go func() {
+			defer close(waiting)
+			gr.Go(func(context.Context) { t.Error("must not run") })
+		}()
+		time.Sleep(10 * time.Millisecond) // let Go wait for the limit
+		drained := make(chan error)
+		go func() { drained <- gr.Drain(context.Background()) }()
+		time.Sleep(10 * time.Millisecond) // let Drain start
+		close(release)
+		assertNoError(t, <-drained)
+		<-waiting
+		assertEqual(t, gr.Stats().Rejected, 1)
+		assertErrorIs(t, gr.Wait(), rungroup.ErrClosed)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.Go(func(ctx context.Context) { <-ctx.Done() })
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(ErrStop)
+		err := gr.Drain(ctx)
+		assertErrorIs(t, err, rungroup.ErrDrainTimeout)
+		assertErrorIs(t, err, ErrStop)
+		assertErrorIs(t, gr.Wait(), rungroup.ErrDrainTimeout)
+	})
+}
@@ -0,0 +1,149 @@
+// Package errgroup is a drop-in replacement for golang.org/x/sync/errgroup
+// built on [rungroup.Group].
+//
+// It has the same API and semantics as golang.org/x/sync/errgroup, so code can
+// be migrated by changing the import path. In addition, the cause of the
+// cancellation of the context returned by [WithContext], as retrieved by
+// [context.Cause], carries the call site of the [Group.Go] call whose function
+// failed, which stacktrace.Format prints.
+//
+// If a function passed to [Group.Go] panics, [Group.Wait] panics with a
+// [*rungroup.PanicError] holding the recovered value, after all functions
+// have returned.
+package errgroup
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/stacktrace/v2"
+)
+
+// A Group is a collection of goroutines working on subtasks that are part of
+// the same overall task.
+//
+// A zero Group is valid, has no limit on the number of active goroutines,
+// and does not cancel on error.
+type Group struct {
+	mu     sync.Mutex
+	gr     *rungroup.Group
+	cancel context.CancelCauseFunc
+
+	active atomic.Int64 // number of goroutines started and not yet returned
+
+	errOnce sync.Once
+	err     error
+
+	panicOnce sync.Once
+	panicErr  *rungroup.PanicError
+}
+
+// WithContext returns a new Group and an associated Context derived from ctx.
+//
+// The derived Context is canceled the first time a function passed to Go
+// returns a non-nil error or the first time Wait returns, whichever occurs
+// first.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{gr: rungroup.New(ctx), cancel: cancel}, ctx
+}
+
+// group returns the underlying [rungroup.Group], creating it for a zero Group.
+func (g *Group) group() *rungroup.Group {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.gr == nil {
+		g.gr = rungroup.New(context.Background())
+	}
+	return g.gr
+}
+
+// Wait blocks until all function calls from the Go method have returned, then
+// returns the first non-nil error (if any) from them.
+func (g *Group) Wait() error {
+	gr := g.group()
+	gr.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	gr.Close()
+	if g.panicErr != nil {
+		panic(g.panicErr)
+	}
+	return g.err
+}
+
+// Go calls the given function in a new goroutine.
+//
+// The first call to return a non-nil error cancels the group's context, if the
+// group was created by calling WithContext. The error will be returned by
+// Wait.
+//
+// If the group has a limit set by SetLimit, Go blocks until the new goroutine
+// can be added without the number of active goroutines in the group exceeding
+// the configured limit.
+func (g *Group) Go(f func() error) {
+	callers := stacktrace.Callers(1)
+	g.active.Add(1)
+	g.group().Go(func(context.Context) { g.run(f, callers) })
+}
+
+// TryGo calls the given function in a new goroutine only if the number of
+// active goroutines in the group is currently below the configured limit.
+//
+// The return value reports whether the goroutine was started.
+func (g *Group) TryGo(f func() error) bool {
+	callers := stacktrace.Callers(1)
+	g.active.Add(1)
+	if !g.group().TryGo(func(context.Context) { g.run(f, callers) }) {
+		g.active.Add(-1)
+		return false
+	}
+	return true
+}
+
+// SetLimit limits the number of active goroutines in this group to at most n.
+// A negative value indicates no limit. A limit of zero will prevent any new
+// goroutines from being added.
+//
+// Any subsequent call to the Go method will block until it can add an active
+// goroutine without exceeding the configured limit.
+//
+// The limit must not be modified while any goroutines in the group are active.
+func (g *Group) SetLimit(n int) {
+	if active := g.active.Load(); active != 0 {
+		panic(fmt.Errorf("errgroup: modify limit while %v goroutines in the group are still active", active))
+	}
+	g.group().SetLimit(n)
+}
+
+// run calls f, which was passed to Go at callers, and records its error or
+// panic.
+func (g *Group) run(f func() error, callers []uintptr) {
+	defer g.active.Add(-1)
+	defer func() {
+		if v := recover(); v != nil {
+			perr := &rungroup.PanicError{Value: v, Stack: debug.Stack()}
+			g.panicOnce.Do(func() { g.panicErr = perr })
+			g.fail(perr, callers)
+		}
+	}()
+	if err := f(); err != nil {
+		g.fail(err, callers)
+	}
+}
+
+// fail records err, returned by the function passed to Go at callers, and
+// cancels the context of the group if err is the first error.
+func (g *Group) fail(err error, callers []uintptr) {
+	g.errOnce.Do(func() {
+		g.err = err
+		if g.cancel != nil {
+			g.cancel(stacktrace.NewError(err, callers))
+		}
+	})
+}
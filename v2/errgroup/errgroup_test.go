@@ -0,0 +1,157 @@
+package errgroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/rungroup/v2/errgroup"
+)
+
+func ExampleWithContext() {
+	g, ctx := errgroup.WithContext(context.Background())
+	g.Go(func() error { return errors.New("failed") })
+	g.Go(func() error { <-ctx.Done(); return ctx.Err() })
+	fmt.Println(g.Wait())
+	fmt.Println(context.Cause(ctx))
+	// Output:
+	// failed
+	// failed (errgroup_test.go:17 ExampleWithContext)
+}
+
+func TestWait(t *testing.T) {
+	ErrA := errors.New("a")
+	ErrB := errors.New("b")
+
+	t.Run("no error", func(t *testing.T) {
+		var g errgroup.Group
+		if err := g.Wait(); err != nil {
+			t.Errorf("Wait without goroutines: %v", err)
+		}
+		g.Go(func() error { return nil })
+		if err := g.Wait(); err != nil {
+			t.Errorf("Wait after a success: %v", err)
+		}
+	})
+
+	t.Run("first error", func(t *testing.T) {
+		var g errgroup.Group
+		g.Go(func() error { return ErrA })
+		if err := g.Wait(); err != ErrA {
+			t.Errorf("Wait = %v, want %v", err, ErrA)
+		}
+		// Later errors do not replace the first one.
+		g.Go(func() error { return ErrB })
+		if err := g.Wait(); err != ErrA {
+			t.Errorf("Wait after a second error = %v, want %v", err, ErrA)
+		}
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	ErrA := errors.New("a")
+
+	t.Run("canceled by Wait", func(t *testing.T) {
+		g, ctx := errgroup.WithContext(context.Background())
+		g.Go(func() error { return nil })
+		if err := g.Wait(); err != nil {
+			t.Errorf("Wait = %v", err)
+		}
+		if ctx.Err() == nil {
+			t.Error("the context is not canceled after Wait")
+		}
+	})
+
+	t.Run("canceled by an error", func(t *testing.T) {
+		g, ctx := errgroup.WithContext(context.Background())
+		g.Go(func() error { return ErrA })
+		<-ctx.Done()
+		if cause := context.Cause(ctx); !errors.Is(cause, ErrA) {
+			t.Errorf("context.Cause = %v, want %v", cause, ErrA)
+		}
+		if err := g.Wait(); err != ErrA {
+			t.Errorf("Wait = %v, want %v", err, ErrA)
+		}
+	})
+}
+
+func TestTryGo(t *testing.T) {
+	var g errgroup.Group
+	g.SetLimit(2)
+	release := make(chan struct{})
+	block := func() error { <-release; return nil }
+	for i := 0; i < 2; i++ {
+		if !g.TryGo(block) {
+			t.Fatalf("TryGo #%d under the limit returned false", i)
+		}
+	}
+	if g.TryGo(block) {
+		t.Fatal("TryGo at the limit returned true")
+	}
+	close(release)
+	g.Wait()
+	if !g.TryGo(func() error { return nil }) {
+		t.Fatal("TryGo after Wait returned false")
+	}
+	g.Wait()
+
+	g.SetLimit(0)
+	if g.TryGo(func() error { return nil }) {
+		t.Fatal("TryGo with a limit of 0 returned true")
+	}
+}
+
+func TestSetLimit(t *testing.T) {
+	const limit = 4
+	var g errgroup.Group
+	g.SetLimit(limit)
+	var active, peak atomic.Int32
+	for i := 0; i < 100; i++ {
+		g.Go(func() error {
+			n := active.Add(1)
+			defer active.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+	}
+	g.Wait()
+	if p := peak.Load(); p > limit || p < 1 {
+		t.Errorf("peak of active goroutines = %d, want 1 to %d", p, limit)
+	}
+}
+
+func TestSetLimitWhileActive(t *testing.T) {
+	g := &errgroup.Group{}
+	release := make(chan struct{})
+	g.Go(func() error { <-release; return nil })
+	defer func() {
+		if recover() == nil {
+			t.Error("SetLimit must panic while goroutines are active")
+		}
+		close(release)
+		g.Wait()
+	}()
+	g.SetLimit(1)
+}
+
+func TestPanic(t *testing.T) {
+	g := &errgroup.Group{}
+	g.Go(func() error { panic("boom") })
+	defer func() {
+		perr, ok := recover().(*rungroup.PanicError)
+		if !ok || perr.Value != "boom" {
+			t.Errorf("Wait must panic with *rungroup.PanicError, got %#v", perr)
+		}
+	}()
+	g.Wait()
+}
@@ -0,0 +1,210 @@
+package rungroup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EventKind is the kind of an [Event].
+type EventKind int
+
+const (
+	// EventTaskStarted is sent just before a task runs.
+	EventTaskStarted EventKind = iota + 1
+
+	// EventTaskFinished is sent just after a task returns.
+	EventTaskFinished
+
+	// EventTaskPanicked is sent instead of EventTaskFinished when a task
+	// panics.
+	EventTaskPanicked
+
+	// EventGroupCanceled is sent when the cancellation of the [Group] is first
+	// observed.
+	EventGroupCanceled
+
+	// EventGroupDone is sent when the [Group] has been canceled and has no
+	// running tasks. It is the last event.
+	EventGroupDone
+)
+
+// String returns the name of k.
+func (k EventKind) String() string {
+	switch k {
+	case EventTaskStarted:
+		return "TaskStarted"
+	case EventTaskFinished:
+		return "TaskFinished"
+	case EventTaskPanicked:
+		return "TaskPanicked"
+	case EventGroupCanceled:
+		return "GroupCanceled"
+	case EventGroupDone:
+		return "GroupDone"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// An Event is a lifecycle event of a [Group], sent to the channels returned by
+// [Group.Subscribe].
+type Event struct {
+	Kind EventKind
+
+	// Task is the task that started or finished. It is zero for the events of
+	// the [Group].
+	Task TaskID
+
+	// Err is the error returned by the task for EventTaskFinished, the
+	// [*PanicError] for EventTaskPanicked, and the cause of the cancellation
+	// for EventGroupCanceled and EventGroupDone.
+	Err error
+
+	// Time is the time of the event, according to the [Clock] of the [Group].
+	Time time.Time
+
+	// Dropped is the number of events that were dropped for the subscriber
+	// just before this one, because its channel was full.
+	Dropped int64
+}
+
+// String returns a short description of e.
+func (e Event) String() string {
+	switch {
+	case e.Task == 0 && e.Err != nil:
+		return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+	case e.Task == 0:
+		return e.Kind.String()
+	case e.Err != nil:
+		return fmt.Sprintf("%v %v: %v", e.Kind, e.Task, e.Err)
+	}
+	return fmt.Sprintf("%v %v", e.Kind, e.Task)
+}
+
+// Subscribe returns a channel that receives the lifecycle events of the
+// [Group], with a buffer of the given size, and a function that stops the
+// subscription.
+//
+// Sending an event never blocks the [Group]. If the channel is full, the
+// event is dropped, and the number of events dropped in a row is reported in
+// the Dropped field of the next event that is delivered. The channel is closed
+// after [EventGroupDone]; if that event is dropped, the channel is closed
+// anyway.
+//
+// Only tasks started after Subscribe returns are reported. Calling
+// unsubscribe stops the delivery of events and closes the channel without
+// [EventGroupDone]; calling it more than once has no effect. Call it when the
+// events are not needed anymore before the [Group] is done, for example when
+// the client of a dashboard disconnects, so that the [Group] does not keep
+// the subscription.
+//
+// Use Cases:
+//
+// Use this to feed a dashboard or a test recorder, without implementing
+// [Hooks].
+func (gr *Group) Subscribe(buffer int) (events <-chan Event, unsubscribe func()) {
+	s := &subscriber{gr: gr, ch: make(chan Event, buffer)}
+	removeHooks := gr.AddHooks(Hooks{
+		TaskStarted: func(id TaskID) {
+			s.send(Event{Kind: EventTaskStarted, Task: id})
+		},
+		TaskFinished: func(id TaskID, err error) {
+			kind := EventTaskFinished
+			if _, ok := err.(*PanicError); ok {
+				kind = EventTaskPanicked
+			}
+			s.send(Event{Kind: kind, Task: id, Err: err})
+		},
+		Canceled: func(cause error) {
+			s.send(Event{Kind: EventGroupCanceled, Err: cause})
+		},
+	})
+	gr.mu.Lock()
+	gr.subscribers = append(gr.subscribers, s)
+	gr.mu.Unlock()
+	gr.checkDone()
+	return s.ch, func() {
+		removeHooks()
+		gr.removeSubscriber(s)
+		s.closeChan()
+	}
+}
+
+// removeSubscriber removes s from the subscribers of the [Group].
+func (gr *Group) removeSubscriber(s *subscriber) {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+	subscribers := make([]*subscriber, 0, len(gr.subscribers))
+	for _, x := range gr.subscribers {
+		if x != s {
+			subscribers = append(subscribers, x)
+		}
+	}
+	gr.subscribers = subscribers
+}
+
+// subscriber is a channel returned by [Group.Subscribe].
+type subscriber struct {
+	gr      *Group
+	mu      sync.Mutex
+	ch      chan Event
+	dropped int64 // number of events dropped since the last delivered one
+	closed  bool
+}
+
+// send sends e to the subscriber, or drops it if the channel is full.
+func (s *subscriber) send(e Event) {
+	e.Time = s.gr.getClock().Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	e.Dropped = s.dropped
+	select {
+	case s.ch <- e:
+		s.dropped = 0
+	default:
+		s.dropped++
+	}
+}
+
+// close sends e, and closes the channel.
+func (s *subscriber) close(e Event) {
+	s.send(e)
+	s.closeChan()
+}
+
+// closeChan closes the channel, if it is not closed yet.
+func (s *subscriber) closeChan() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// checkDone sends [EventGroupDone] to the subscribers, and closes their
+// channels, if the [Group] has been canceled and has no running tasks.
+func (gr *Group) checkDone() {
+	ctx := gr.getContext()
+	if ctx.Err() == nil || gr.active.Load() != 0 {
+		return
+	}
+	gr.mu.Lock()
+	subscribers := gr.subscribers
+	gr.subscribers = nil
+	gr.mu.Unlock()
+	if len(subscribers) == 0 {
+		return
+	}
+	// The cancellation may not have been observed yet, if the parent context
+	// was canceled.
+	gr.notifyCanceled()
+	e := Event{Kind: EventGroupDone, Err: context.Cause(ctx)}
+	for _, s := range subscribers {
+		s.close(e)
+	}
+}
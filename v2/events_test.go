@@ -0,0 +1,81 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestGroup_Subscribe(t *testing.T) {
+	t.Run("events", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		var gr rungroup.Group
+		events, _ := gr.Subscribe(16)
+		started := make(chan struct{})
+		gr.Go(func(ctx context.Context) { close(started); <-ctx.Done() })
+		<-started
+		gr.Go(func(context.Context) { panic("boom") })
+		gr.Wait()
+		gr.Cancel(ErrStop) // after the panic, so it is not the cause
+
+		var kinds []string
+		for e := range events {
+			assertEqual(t, e.Dropped, int64(0))
+			kinds = append(kinds, e.Kind.String())
+			if e.Kind == rungroup.EventGroupDone {
+				var pe *rungroup.PanicError
+				assertEqual(t, errors.As(e.Err, &pe), true)
+			}
+		}
+		expect := []string{"TaskStarted", "TaskStarted", "TaskPanicked", "GroupCanceled", "TaskFinished", "GroupDone"}
+		assertEqual(t, len(kinds), len(expect))
+		if len(kinds) == len(expect) {
+			for i := range expect {
+				assertEqual(t, kinds[i], expect[i])
+			}
+		}
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		var gr rungroup.Group
+		events, _ := gr.Subscribe(1)
+		for i := 0; i < 3; i++ {
+			gr.Go(func(context.Context) {})
+			gr.Wait()
+		}
+		e := <-events
+		assertEqual(t, e.Kind, rungroup.EventTaskStarted)
+		assertEqual(t, e.Dropped, int64(0))
+		gr.Close()
+		e, ok := <-events
+		assertEqual(t, ok, true)
+		assertEqual(t, e.Kind, rungroup.EventGroupCanceled)
+		assertEqual(t, e.Dropped, int64(5))
+		// GroupDone is dropped as well, but the channel is closed.
+		_, ok = <-events
+		assertEqual(t, ok, false)
+	})
+
+	t.Run("already done", func(t *testing.T) {
+		var gr rungroup.Group
+		gr.Close()
+		events, _ := gr.Subscribe(1)
+		e := <-events
+		assertEqual(t, e.Kind, rungroup.EventGroupDone)
+		assertErrorIs(t, e.Err, rungroup.ErrClosed)
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		events, unsubscribe := gr.Subscribe(16)
+		unsubscribe()
+		unsubscribe()
+		gr.Go(func(context.Context) {})
+		gr.Wait()
+		_, ok := <-events
+		assertEqual(t, ok, false)
+	})
+}
@@ -10,7 +10,42 @@
 //     [Group.GoCancelOnSuccess], and [Group.GoCancelOnError].
 //   - Waiting for all goroutines to finish with [Group.Wait].
 //   - Canceling all goroutines with [Group.Cancel] or [Group.Close].
-//   - Setting a timeout for the group with [Group.SetTimeout].
+//   - Setting a timeout for the group with [Group.SetTimeout], and for
+//     stalled tasks with [Group.GoWatched].
+//   - Waiting for tasks to become ready with [Group.GoReady],
+//     [Group.WaitReady] and [Group.GoStages].
+//   - Retrieving the [Group] and the identity of the running task from the
+//     task's context with [FromContext].
+//   - Observing the lifecycle of tasks with [Group.AddHooks], and replacing
+//     the source of time with [Group.SetClock].
+//   - Receiving the lifecycle events of the group on a channel with
+//     [Group.Subscribe].
+//   - Naming tasks with [Group.GoNamed], and collecting statistics about them
+//     with [Group.Stats].
+//   - Failing fast while the tasks of a name keep failing with
+//     [Group.SetCircuitBreaker].
+//   - Running tasks on a pool of reusable goroutines with [Group.SetPool].
+//   - Limiting the number of active tasks with [Group.SetLimit] and
+//     [Group.TryGo], and their total weight with [Group.SetCapacity] and
+//     [Group.GoWeighted]. The limit can also adapt to the latency and errors
+//     of the tasks with [Group.SetAdaptiveLimit].
+//   - Canceling the group after a period with no running tasks with
+//     [Group.SetIdleTimeout].
+//   - Recording the attempts to cancel the group with
+//     [Group.CancelHistory].
+//   - Propagating the cancellation between groups with [Link], and waiting
+//     for several groups with [WaitAll] and [WaitAny].
+//   - Turning a group into the exit code of a program with [Main].
+//   - Running the tasks that share a key one at a time with
+//     [Group.GoKeyed].
+//   - Coalescing concurrent calls for the same key into one task with
+//     [GoShared].
+//   - Finishing the running tasks before canceling with [Group.Drain].
+//   - Controlling a single task through a [Task] handle returned by
+//     [Group.GoTask].
+//   - Reporting the outcome of every task with [Group.Report].
+//   - Inspecting the tree of live groups and their running tasks with
+//     [EnableRegistry] and [Groups].
 //
 // This package is useful for scenarios where you need to execute multiple
 // tasks concurrently and ensure that they are properly managed and
@@ -44,7 +79,11 @@ package rungroup
 import (
 	"context"
 	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/goaux/stacktrace/v2"
@@ -69,8 +108,37 @@ type Group struct {
 	mu sync.Mutex
 	g  waitgroup.Sync
 
-	ctx    context.Context
-	cancel context.CancelCauseFunc
+	gc          atomic.Pointer[groupContext]
+	callerDepth atomic.Int64 // 0: unlimited, -1: disabled, n > 0: n frames
+
+	notReady int           // number of tasks that have not signalled readiness
+	readyCh  chan struct{} // closed when notReady drops to zero
+	readyErr error         // why a task returned without signalling readiness
+
+	clock        atomic.Pointer[Clock]
+	stats        stats
+	report       atomic.Pointer[report]
+	debug        atomic.Pointer[debugState]
+	pool         atomic.Pointer[pool]
+	sem          atomic.Pointer[chan struct{}] // limits the number of active tasks, or nil
+	capacity     atomic.Pointer[weighted]      // limits the total weight of active tasks, or nil
+	adaptive     atomic.Pointer[adaptive]      // adapts the limit of active tasks, or nil
+	breaker      atomic.Pointer[breaker]       // circuit breakers by task name, or nil
+	draining     atomic.Bool
+	drainMu      sync.RWMutex                // orders the admission of tasks with Drain
+	active       atomic.Int64                // number of tasks started and not yet finished
+	idle         atomic.Pointer[idleWatcher] // watcher of SetIdleTimeout, or nil
+	history      []cancelAttempt             // protected by mu
+	joinCauses   atomic.Bool
+	panicked     atomic.Pointer[PanicError] // first panic of a task, reported by Wait
+	links        []*Group                   // protected by mu
+	keys         map[string]*keyQueue       // queued tasks of GoKeyed by key, protected by mu
+	shared       map[sharedKey]*sharedCall  // running tasks of GoShared, protected by mu
+	subscribers  []*subscriber              // channels returned by Subscribe, protected by mu
+	hooks        atomic.Pointer[[]Hooks]
+	hookIDs      []uint64  // identifies the elements of hooks for removal, protected by mu
+	lastHookID   uint64    // protected by mu
+	canceledOnce sync.Once // guards the call of Hooks.Canceled
 }
 
 // New returns a Group initialized with parent as its parent context.
@@ -85,13 +153,24 @@ type Group struct {
 // with `New`. Failing to do so will result in a resource leak.
 func New(parent context.Context) *Group {
 	ctx, cancel := context.WithCancelCause(parent)
-	return &Group{ctx: ctx, cancel: cancel}
+	gr := &Group{}
+	gr.gc.Store(&groupContext{ctx: ctx, cancel: cancel})
+	if registry.enabled.Load() {
+		gr.register(parent, stacktrace.Callers(1))
+	}
+	return gr
+}
+
+// groupContext is the context of a [Group] along with its cancel function.
+type groupContext struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
 }
 
 // Close cancels the [Group] by calling [Group.Cancel] with [ErrClosed],
 // thereby releasing its associated resources.
 func (gr *Group) Close() {
-	gr.Cancel(stacktrace.NewError(ErrClosed, stacktrace.Callers(1)))
+	gr.cancelCause(stacktrace.NewError(ErrClosed, gr.callers(1)))
 }
 
 // Cancel cancels the context for a [Group].
@@ -103,27 +182,109 @@ func (gr *Group) Cancel(cause error) {
 	if cause == nil {
 		cause = context.Canceled
 	}
-	gr.cancel(stacktrace.NewError(cause, stacktrace.Callers(1)))
+	gr.cancelCause(stacktrace.NewError(cause, gr.callers(1)))
+}
+
+// cancelCause cancels the context for the [Group] with cause, and reports the
+// cancellation to the hooks.
+//
+// It reports whether this call canceled the context, i.e. whether cause is the
+// cause of the cancellation.
+func (gr *Group) cancelCause(cause error) (first bool) {
+	gc := gr.getGroupContext()
+	gr.mu.Lock()
+	first = gc.ctx.Err() == nil
+	gc.cancel(cause)
+	gr.recordCancel(cause, first)
+	gr.mu.Unlock()
+	gr.notifyCanceled()
+	if first {
+		gr.propagateCancel(cause)
+	}
+	gr.unregisterIfDone()
+	return first
 }
 
 // Wait blocks until all goroutines have exited.
 // It returns the argument passed to the first [Group.Cancel] call, or nil if
-// [Group.Cancel] was never called.
+// [Group.Cancel] was never called. See [Group.SetJoinCauses] for including the
+// arguments of the later calls. If a task panicked, the error matches the
+// [*PanicError] of the first panic as well.
 func (gr *Group) Wait() error {
-	gr.getContext()
+	ctx := gr.getContext()
 	gr.g.Wait()
-	return context.Cause(gr.ctx)
+	if ctx.Err() != nil {
+		gr.notifyCanceled()
+	}
+	return gr.waitCause(ctx)
 }
 
 // getContext returns the context for the [Group].
 // The context associated with the [Group] will be cancelled when [Group.Cancel] is invoked.
 func (gr *Group) getContext() context.Context {
+	return gr.getGroupContext().ctx
+}
+
+// getGroupContext returns the context for the [Group] along with its cancel
+// function, initializing them for a zero-value [Group].
+//
+// Once initialized, it does not take gr.mu.
+func (gr *Group) getGroupContext() *groupContext {
+	if gc := gr.gc.Load(); gc != nil {
+		return gc
+	}
 	gr.mu.Lock()
 	defer gr.mu.Unlock()
-	if gr.ctx == nil {
-		gr.ctx, gr.cancel = context.WithCancelCause(context.Background())
+	if gc := gr.gc.Load(); gc != nil {
+		return gc
 	}
-	return gr.ctx
+	ctx, cancel := context.WithCancelCause(context.Background())
+	gc := &groupContext{ctx: ctx, cancel: cancel}
+	gr.gc.Store(gc)
+	gr.register(context.Background(), nil)
+	return gc
+}
+
+// SetCallerDepth sets the maximum number of stack frames that the [Group]
+// captures for the call sites of tasks and cancellations, which appear in the
+// causes returned by [Group.Wait] and formatted by stacktrace.Format.
+//
+// A depth of zero disables the capture, and the causes carry no call site.
+// By default the whole stack is captured.
+//
+// Call sites are captured when a task is started, but only if they may be
+// needed: for tasks that may cancel the [Group], or while the [Group] is
+// recorded for [Group.Report] or by [EnableRegistry]. Limiting or disabling
+// the capture makes starting such tasks cheaper.
+func (gr *Group) SetCallerDepth(depth int) {
+	if depth <= 0 {
+		depth = -1
+	}
+	gr.callerDepth.Store(int64(depth))
+}
+
+// callers returns the call stack of the caller of the function that calls
+// callers, skipping skip additional frames, as limited by
+// [Group.SetCallerDepth].
+func (gr *Group) callers(skip int) []uintptr {
+	switch depth := gr.callerDepth.Load(); {
+	case depth < 0:
+		return nil
+	case depth == 0:
+		return stacktrace.Callers(skip + 1)
+	default:
+		pc := make([]uintptr, depth)
+		return pc[:runtime.Callers(skip+2, pc)]
+	}
+}
+
+// taskCallers is like callers, but returns nil without capturing the call stack
+// if it is not needed for a task with policy.
+func (gr *Group) taskCallers(policy Policy, skip int) []uintptr {
+	if policy == CancelNever && gr.report.Load() == nil && gr.debug.Load() == nil {
+		return nil
+	}
+	return gr.callers(skip + 1)
 }
 
 // Go allows you to start a task in a new goroutine and synchronize its
@@ -141,11 +302,210 @@ func (gr *Group) getContext() context.Context {
 // These methods are essentially macros around [Group.Go], internally calling
 // both [Group.Go] and [Group.Cancel].
 //
-// The [Group]'s context is passed to the task.
+// If the task panics, the panic is recovered, and the [Group] is canceled with
+// a [*PanicError] holding the value passed to panic. Unlike a panic in a
+// goroutine started with a go statement, it does not crash the program: it is
+// reported only by the error returned by [Group.Wait], by [Group.Stats] and by
+// [Hooks], so callers that ignore the error of Wait lose the panic. The error
+// returned by Wait matches the [*PanicError] of the first panic even if the
+// [Group] had already been canceled with another cause.
+//
+// The [Group]'s context is passed to the task, along with the identity of the
+// task, which can be retrieved with [FromContext].
 // When [Group.Cancel] is called, the [Group]'s context is cancelled.
 func (gr *Group) Go(task func(context.Context)) {
-	ctx := gr.getContext()
-	gr.g.Go(func() { task(ctx) })
+	gr.goTask(taskSpec{callers: gr.taskCallers(CancelNever, 1), fn: task}, nil)
+}
+
+// GoNamed starts a task named name, and cancels the [Group] upon completion
+// of the task according to policy.
+//
+// GoNamed with [CancelNever] is equivalent to [Group.Go], and GoNamed with
+// [CancelOnFinish], [CancelOnSuccess] or [CancelOnError] is equivalent to
+// [Group.GoCancelOnFinish], [Group.GoCancelOnSuccess] or
+// [Group.GoCancelOnError], except that the task is named.
+//
+// The name identifies the task in [Group.Stats]. Tasks started with the other
+// methods have an empty name.
+func (gr *Group) GoNamed(name string, policy Policy, task func(context.Context) error) {
+	gr.goTask(taskSpec{name: name, callers: gr.taskCallers(policy, 1), policy: policy}, task)
+}
+
+// taskSpec describes a task started by goTask.
+type taskSpec struct {
+	name    string    // name of the task
+	callers []uintptr // call site of the method that started the task
+	policy  Policy    // whether the completion of the task cancels the Group
+
+	fn func(context.Context) // the task started by Group.Go, if the task passed to goTask is nil
+
+	ctx    context.Context // context of the task, derived from the Group's context, or nil for the Group's context
+	handle *Task           // handle of the task, or nil
+	try    bool            // whether to give up instead of waiting for the limit set by SetLimit
+	weight int             // units of the capacity set by SetCapacity that the task holds
+}
+
+// goTask starts task, or spec.fn if task is nil, in a new goroutine, and
+// cancels the [Group] upon completion of the task according to spec.policy.
+// It returns the ID of the task, or zero if the task was not started, either
+// because the [Group] is draining, because spec.try is set and the limit set
+// by [Group.SetLimit] is reached, or because the [Group] was canceled while
+// waiting for the capacity set by [Group.SetCapacity].
+//
+// The hooks of the [Group] are called, and the statistics and the report of
+// the [Group] are updated, around task. If task panics, the panic is
+// recovered, and the [Group] is canceled with a [*PanicError] regardless of
+// spec.policy.
+func (gr *Group) goTask(spec taskSpec, task func(context.Context) error) TaskID {
+	if !gr.enter() {
+		gr.reject(spec.handle)
+		return 0
+	}
+	parent := gr.getContext()
+	admission := gr.admit(spec.name)
+	releaseWeight, releaseLimit, limiter := func() {}, func() {}, (*adaptive)(nil)
+	if admission.open {
+		// The task fails fast, without waiting for the limits.
+		task = circuitOpen(spec.name)
+	} else {
+		var ok bool
+		releaseWeight, ok = gr.acquireWeight(parent, spec.weight)
+		if !ok {
+			gr.record(admission, spec.name, false, true)
+			gr.g.Done()
+			return 0
+		}
+		releaseLimit, ok = gr.acquire(spec.try)
+		if !ok {
+			releaseWeight()
+			gr.record(admission, spec.name, false, true)
+			gr.g.Done()
+			return 0
+		}
+		limiter, ok = gr.acquireAdaptive(spec.try)
+		if !ok {
+			releaseLimit()
+			releaseWeight()
+			gr.record(admission, spec.name, false, true)
+			gr.g.Done()
+			return 0
+		}
+	}
+	if gr.draining.Load() {
+		// Drain was called while the task was waiting for the limits.
+		limiter.release()
+		releaseLimit()
+		releaseWeight()
+		gr.record(admission, spec.name, false, true)
+		gr.reject(spec.handle)
+		gr.g.Done()
+		return 0
+	}
+	base := spec.ctx
+	if base == nil {
+		base = parent
+	}
+	ctx, id := withTask(base, gr)
+	gr.active.Add(1)
+	gr.signalIdle()
+	gr.spawn(func() {
+		defer gr.g.Done()
+		defer releaseWeight()
+		defer releaseLimit()
+		defer limiter.release()
+		defer gr.deactivate()
+		hooks := gr.getHooks()
+		for _, h := range hooks {
+			if h.TaskStarted != nil {
+				h.TaskStarted(id)
+			}
+		}
+		clock := gr.getClock()
+		start := gr.stats.start(clock)
+		runningAtStart := parent.Err() == nil
+		if d := gr.debug.Load(); d != nil {
+			d.tasks.Store(id, &liveTask{name: spec.name, callers: spec.callers, start: start})
+		}
+		err, panicked := call(ctx, task, spec.fn)
+		if d := gr.debug.Load(); d != nil {
+			d.tasks.Delete(id)
+		}
+		canceledWhileRunning := runningAtStart && parent.Err() != nil
+		if canceledWhileRunning {
+			gr.notifyCanceled()
+		}
+		end := gr.stats.finish(clock, spec.name, start, err, panicked)
+		if parent.Err() == nil {
+			limiter.observe(end.Sub(start), err != nil)
+		}
+		gr.record(admission, spec.name, err != nil, parent.Err() != nil)
+		for _, h := range hooks {
+			if h.TaskFinished != nil {
+				h.TaskFinished(id, err)
+			}
+		}
+		triggered := false
+		if panicked {
+			gr.panicked.CompareAndSwap(nil, err.(*PanicError))
+			triggered = gr.cancelCause(err)
+		} else {
+			triggered = gr.applyPolicy(spec.policy, spec.callers, err)
+		}
+		if r := gr.report.Load(); r != nil {
+			r.add(TaskReport{
+				ID:        id,
+				Name:      spec.name,
+				CallSite:  callSite(spec.callers),
+				Start:     start,
+				End:       end,
+				Outcome:   outcomeOf(err, panicked, canceledWhileRunning),
+				Err:       err,
+				Triggered: triggered,
+			})
+		}
+		if t := spec.handle; t != nil {
+			t.finish(err)
+		}
+		gr.unregisterIfDone()
+	})
+	return id
+}
+
+// call calls task, or fn if task is nil, with ctx, and recovers a panic as a
+// [*PanicError].
+func call(ctx context.Context, task func(context.Context) error, fn func(context.Context)) (err error, panicked bool) {
+	defer func() {
+		if v := recover(); v != nil {
+			err, panicked = &PanicError{Value: v, Stack: debug.Stack()}, true
+		}
+	}()
+	if task == nil {
+		fn(ctx)
+		return nil, false
+	}
+	return task(ctx), false
+}
+
+// A PanicError is the cause with which a [Group] is canceled when one of its
+// tasks panics.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace of the goroutine at the time of the panic, as
+	// formatted by [debug.Stack].
+	Stack []byte
+}
+
+// Error returns a description of the panic.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns Value if it is an error, or nil otherwise.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
 }
 
 // SetTimeout cancels the group's context after the timeout duration has elapsed.
@@ -155,18 +515,61 @@ func (gr *Group) Go(task func(context.Context)) {
 // canceled before the timeout, [Group.Cancel] is not called.
 func (gr *Group) SetTimeout(timeout time.Duration) {
 	ctx := gr.getContext()
-	callers := stacktrace.Callers(1)
+	callers := gr.callers(1)
+	t := gr.getClock().NewTimer(timeout)
 	go func() {
-		t := time.NewTimer(timeout)
 		defer t.Stop()
 		select {
-		case <-t.C:
-			gr.cancel(stacktrace.NewError(context.DeadlineExceeded, callers))
+		case <-t.C():
+			gr.cancelCause(stacktrace.NewError(context.DeadlineExceeded, callers))
 		case <-ctx.Done():
 		}
 	}()
 }
 
+// A Policy tells whether the completion of a task cancels the [Group].
+type Policy int
+
+const (
+	// CancelNever does not cancel the [Group], like [Group.Go].
+	CancelNever Policy = iota
+
+	// CancelOnFinish cancels the [Group] when the task finishes, like
+	// [Group.GoCancelOnFinish].
+	CancelOnFinish
+
+	// CancelOnSuccess cancels the [Group] when the task returns a nil error,
+	// like [Group.GoCancelOnSuccess].
+	CancelOnSuccess
+
+	// CancelOnError cancels the [Group] when the task returns a non-nil error,
+	// like [Group.GoCancelOnError].
+	CancelOnError
+)
+
+// applyPolicy cancels the [Group] according to policy, given the error err
+// returned by a task started at callers.
+//
+// It reports whether the [Group] has been canceled by this call.
+func (gr *Group) applyPolicy(policy Policy, callers []uintptr, err error) bool {
+	switch policy {
+	case CancelOnFinish:
+		if err == nil {
+			err = context.Canceled
+		}
+		return gr.cancelCause(stacktrace.NewError(err, callers))
+	case CancelOnSuccess:
+		if err == nil { // if NO error
+			return gr.cancelCause(stacktrace.NewError(context.Canceled, callers))
+		}
+	case CancelOnError:
+		if err != nil {
+			return gr.cancelCause(stacktrace.NewError(err, callers))
+		}
+	}
+	return false
+}
+
 // GoCancelOnFinish starts a task using [Group.Go] and, when that task
 // finishes, cancels all other running tasks in the group using [Group.Cancel].
 //
@@ -177,14 +580,7 @@ func (gr *Group) SetTimeout(timeout time.Duration) {
 // For example, imagine a primary task and several helper tasks. If the primary
 // task completes, you might want to stop the helpers immediately.
 func (gr *Group) GoCancelOnFinish(task func(context.Context) error) {
-	callers := stacktrace.Callers(1)
-	gr.Go(func(ctx context.Context) {
-		err := task(ctx)
-		if err == nil {
-			err = context.Canceled
-		}
-		gr.cancel(stacktrace.NewError(err, callers))
-	})
+	gr.goTask(taskSpec{callers: gr.callers(1), policy: CancelOnFinish}, task)
 }
 
 // GoCancelOnSuccess starts a task using [Group.Go] and, if the task completes
@@ -199,12 +595,7 @@ func (gr *Group) GoCancelOnFinish(task func(context.Context) error) {
 // different ways. You'd want to use the result from the task that finishes
 // first.
 func (gr *Group) GoCancelOnSuccess(task func(context.Context) error) {
-	callers := stacktrace.Callers(1)
-	gr.Go(func(ctx context.Context) {
-		if err := task(ctx); err == nil { // if NO error
-			gr.cancel(stacktrace.NewError(context.Canceled, callers))
-		}
-	})
+	gr.goTask(taskSpec{callers: gr.callers(1), policy: CancelOnSuccess}, task)
 }
 
 // GoCancelOnError calls [Group.Go] to start a task, and if the task returns a
@@ -217,10 +608,5 @@ func (gr *Group) GoCancelOnSuccess(task func(context.Context) error) {
 // Imagine a big task split into smaller parts done at the same time. If one
 // part fails, you can't complete the whole thing.
 func (gr *Group) GoCancelOnError(task func(context.Context) error) {
-	callers := stacktrace.Callers(1)
-	gr.Go(func(ctx context.Context) {
-		if err := task(ctx); err != nil {
-			gr.cancel(stacktrace.NewError(err, callers))
-		}
-	})
+	gr.goTask(taskSpec{callers: gr.callers(1), policy: CancelOnError}, task)
 }
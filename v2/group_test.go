@@ -109,6 +109,29 @@ func TestGroup(t *testing.T) {
 		assertEqual(t, n, 2)
 	})
 
+	t.Run("SetCallerDepth", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		for _, tt := range []struct {
+			depth  int
+			frames int
+		}{
+			{0, 0},
+			{1, 1},
+		} {
+			var gr rungroup.Group
+			gr.SetCallerDepth(tt.depth)
+			gr.GoCancelOnError(func(context.Context) error { return ErrStop })
+			err := gr.Wait()
+			assertErrorIs(t, err, ErrStop)
+			var st interface{ StackTrace() []uintptr }
+			if !errors.As(err, &st) {
+				t.Fatalf("err=%#v, want a stack tracer", err)
+			}
+			assertEqual(t, len(st.StackTrace()), tt.frames, "depth=", tt.depth)
+			gr.Close()
+		}
+	})
+
 	ErrStop := errors.New("stop")
 
 	t.Run("GoCnacelOnFinish", func(t *testing.T) {
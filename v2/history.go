@@ -0,0 +1,131 @@
+package rungroup
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goaux/stacktrace/v2"
+)
+
+// CancelAttempt describes one attempt to cancel a [Group]. See
+// [Group.CancelHistory].
+type CancelAttempt struct {
+	// Cause is the cause passed to the attempt.
+	Cause error
+
+	// CallSite is the location that made the attempt, formatted as
+	// "file.go:line function". It is empty if the location is not known.
+	CallSite string
+
+	// Time is the time of the attempt, according to the [Clock] of the
+	// [Group].
+	Time time.Time
+
+	// First reports whether the attempt canceled the [Group], i.e. whether
+	// Cause is the cause of the cancellation.
+	First bool
+}
+
+// maxCancelHistory is the maximum number of attempts kept by
+// [Group.CancelHistory].
+const maxCancelHistory = 64
+
+// cancelAttempt is a [CancelAttempt] whose call site is not formatted yet.
+type cancelAttempt struct {
+	cause   error
+	callers []uintptr // call site of the attempt, or nil
+	time    time.Time
+	first   bool
+}
+
+// CancelHistory returns the attempts to cancel the [Group], in the order in
+// which they were made.
+//
+// The context of a [Group] keeps only the cause of the first cancellation, and
+// drops the causes passed after it. CancelHistory records the others as well:
+// calls to [Group.Cancel] and [Group.Close], timeouts, and the tasks whose
+// [Policy] asked for a cancellation. A cancellation of the parent context
+// passed to [New] is not an attempt of the [Group], and is not recorded.
+//
+// To bound the memory used by a long-lived [Group], CancelHistory keeps at
+// most 64 attempts: the earliest one, which canceled the [Group], and the most
+// recent ones.
+//
+// Use Cases:
+//
+// Use this to debug a shutdown, for example to see that a timeout was followed
+// by the error of a task and then by a call to [Group.Close].
+func (gr *Group) CancelHistory() []CancelAttempt {
+	gr.mu.Lock()
+	history := append([]cancelAttempt(nil), gr.history...)
+	gr.mu.Unlock()
+	if len(history) == 0 {
+		return nil
+	}
+	attempts := make([]CancelAttempt, len(history))
+	for i, a := range history {
+		attempts[i] = CancelAttempt{
+			Cause:    a.cause,
+			CallSite: callSite(a.callers),
+			Time:     a.time,
+			First:    a.first,
+		}
+	}
+	return attempts
+}
+
+// SetJoinCauses sets whether [Group.Wait] includes the secondary causes of the
+// cancellation in the error it returns.
+//
+// By default, Wait returns only the cause of the first cancellation. If join
+// is true, Wait returns the cause of the first cancellation joined, with
+// [errors.Join], with the causes of the later attempts kept by
+// [Group.CancelHistory].
+func (gr *Group) SetJoinCauses(join bool) {
+	gr.joinCauses.Store(join)
+}
+
+// recordCancel records an attempt to cancel the [Group] with cause. If the
+// history is full, it drops the oldest attempt after the earliest one.
+//
+// gr.mu must be held.
+func (gr *Group) recordCancel(cause error, first bool) {
+	a := cancelAttempt{cause: cause, time: gr.getClock().Now(), first: first}
+	if st := stacktrace.ListStackTracers(cause); len(st) != 0 {
+		a.callers = st[0].StackTrace()
+	}
+	if len(gr.history) == maxCancelHistory {
+		copy(gr.history[1:], gr.history[2:])
+		gr.history = gr.history[:len(gr.history)-1]
+	}
+	gr.history = append(gr.history, a)
+}
+
+// waitCause returns the error for [Group.Wait], given the context of the
+// [Group]. The error includes the [*PanicError] of the first panic of a task,
+// if any, even when the cause of the cancellation is another one.
+func (gr *Group) waitCause(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	if cause == nil {
+		return nil
+	}
+	errs := []error{cause}
+	if gr.joinCauses.Load() {
+		gr.mu.Lock()
+		for _, a := range gr.history {
+			if !a.first {
+				errs = append(errs, a.cause)
+			}
+		}
+		gr.mu.Unlock()
+	}
+	if pe := gr.panicked.Load(); pe != nil && !errors.Is(errors.Join(errs...), pe) {
+		// A task panicked after the Group was canceled with another cause.
+		errs = append(errs, pe)
+	}
+	if len(errs) == 1 {
+		return cause
+	}
+	return errors.Join(errs...)
+}
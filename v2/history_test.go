@@ -0,0 +1,83 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/rungroup/v2/rungrouptest"
+)
+
+func TestGroup_CancelHistory(t *testing.T) {
+	ErrFirst := errors.New("first")
+	ErrTask := errors.New("task")
+	start := time.Unix(0, 0)
+
+	run := func(join bool) (*rungroup.Group, error) {
+		clock := rungrouptest.NewClock(start)
+		gr := new(rungroup.Group)
+		gr.SetClock(clock)
+		gr.SetJoinCauses(join)
+		assertEqual(t, len(gr.CancelHistory()), 0)
+		gr.Cancel(ErrFirst)
+		clock.Advance(time.Second)
+		gr.GoCancelOnError(func(context.Context) error { return ErrTask })
+		gr.Wait()
+		clock.Advance(time.Second)
+		gr.Close()
+		return gr, gr.Wait()
+	}
+
+	t.Run("history", func(t *testing.T) {
+		gr, err := run(false)
+		assertErrorIs(t, err, ErrFirst)
+		if errors.Is(err, ErrTask) {
+			t.Errorf("Wait returned a secondary cause: %v", err)
+		}
+		history := gr.CancelHistory()
+		assertEqual(t, len(history), 3)
+		if len(history) != 3 {
+			return
+		}
+		for i, expect := range []error{ErrFirst, ErrTask, rungroup.ErrClosed} {
+			a := history[i]
+			assertErrorIs(t, a.Cause, expect)
+			assertEqual(t, a.First, i == 0)
+			assertEqual(t, a.Time, start.Add(time.Duration(i)*time.Second))
+			if !strings.HasPrefix(a.CallSite, "history_test.go:") {
+				t.Errorf("CallSite of %v: %q", expect, a.CallSite)
+			}
+		}
+	})
+
+	t.Run("join causes", func(t *testing.T) {
+		_, err := run(true)
+		assertErrorIs(t, err, ErrFirst)
+		assertErrorIs(t, err, ErrTask)
+		assertErrorIs(t, err, rungroup.ErrClosed)
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		var gr rungroup.Group
+		gr.Cancel(ErrFirst)
+		for i := 0; i < 1000; i++ {
+			gr.Cancel(ErrTask)
+		}
+		gr.Close()
+		history := gr.CancelHistory()
+		assertEqual(t, len(history), 64)
+		assertErrorIs(t, history[0].Cause, ErrFirst)
+		assertEqual(t, history[0].First, true)
+		assertErrorIs(t, history[62].Cause, ErrTask)
+		assertErrorIs(t, history[63].Cause, rungroup.ErrClosed)
+	})
+
+	t.Run("not canceled", func(t *testing.T) {
+		var gr rungroup.Group
+		gr.SetJoinCauses(true)
+		assertNoError(t, gr.Wait())
+	})
+}
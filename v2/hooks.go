@@ -0,0 +1,101 @@
+package rungroup
+
+import "context"
+
+// Hooks are callbacks that a [Group] calls at points in its lifecycle.
+//
+// Any of the fields may be nil. The callbacks may be called concurrently from
+// multiple goroutines, and must not block.
+type Hooks struct {
+	// TaskStarted is called in the goroutine of a task, just before the task
+	// runs.
+	TaskStarted func(id TaskID)
+
+	// TaskFinished is called in the goroutine of a task, just after the task
+	// returns. err is the error returned by the task, or a [*PanicError] if
+	// the task panicked. For tasks started with [Group.Go], err is nil unless
+	// the task panicked.
+	TaskFinished func(id TaskID, err error)
+
+	// TaskRejected is called when a task is not started, either because the
+	// [Group] is draining, with [ErrDraining] as err, or because the task was
+	// queued by [Group.GoKeyed] and the [Group] was canceled, with
+	// [ErrSkipped] as err.
+	TaskRejected func(err error)
+
+	// CircuitChanged is called when the state of the circuit breaker of the
+	// task name changes from from to to. See [Group.SetCircuitBreaker].
+	CircuitChanged func(name string, from, to CircuitState)
+
+	// Canceled is called once, when the cancellation of the [Group]'s context
+	// is first observed, with the cause of the cancellation.
+	//
+	// Canceled is called before TaskFinished of any task that finishes after
+	// the cancellation has been observed. It must not call [Group.Cancel] or
+	// [Group.Close] on the same [Group].
+	Canceled func(cause error)
+}
+
+// AddHooks adds h to the hooks of the [Group], and returns a function that
+// removes them.
+//
+// The hooks apply to tasks started after AddHooks returns. Tasks that are
+// already running are not reported. Once remove has returned, the hooks are
+// not called anymore for the tasks started after that; calling remove more
+// than once has no effect.
+func (gr *Group) AddHooks(h Hooks) (remove func()) {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+	var hooks []Hooks
+	if p := gr.hooks.Load(); p != nil {
+		hooks = append(hooks, *p...)
+	}
+	hooks = append(hooks, h)
+	gr.lastHookID++
+	id := gr.lastHookID
+	gr.hookIDs = append(gr.hookIDs, id)
+	gr.hooks.Store(&hooks)
+	return func() { gr.removeHooks(id) }
+}
+
+// removeHooks removes the hooks added by the call to [Group.AddHooks]
+// identified by id.
+func (gr *Group) removeHooks(id uint64) {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+	old := gr.getHooks()
+	hooks := make([]Hooks, 0, len(old))
+	ids := make([]uint64, 0, len(old))
+	for i, h := range old {
+		if gr.hookIDs[i] != id {
+			hooks = append(hooks, h)
+			ids = append(ids, gr.hookIDs[i])
+		}
+	}
+	gr.hookIDs = ids
+	gr.hooks.Store(&hooks)
+}
+
+// getHooks returns the hooks of the [Group].
+func (gr *Group) getHooks() []Hooks {
+	if p := gr.hooks.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// notifyCanceled calls the Canceled hooks once, after the cancellation of the
+// [Group]'s context, and then checks whether the [Group] is done for the
+// subscribers of [Group.Subscribe].
+func (gr *Group) notifyCanceled() {
+	gr.canceledOnce.Do(func() {
+		gr.stats.cancel(gr.getClock())
+		cause := context.Cause(gr.getContext())
+		for _, h := range gr.getHooks() {
+			if h.Canceled != nil {
+				h.Canceled(cause)
+			}
+		}
+	})
+	gr.checkDone()
+}
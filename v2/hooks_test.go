@@ -0,0 +1,61 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestGroup_AddHooks(t *testing.T) {
+	ErrStop := errors.New("stop")
+	var mu sync.Mutex
+	var events []string
+	record := func(s string) { mu.Lock(); defer mu.Unlock(); events = append(events, s) }
+
+	var gr rungroup.Group
+	defer gr.Close()
+	gr.AddHooks(rungroup.Hooks{
+		TaskStarted:  func(rungroup.TaskID) { record("started") },
+		TaskFinished: func(_ rungroup.TaskID, err error) { record("finished " + errString(err)) },
+		Canceled:     func(cause error) { record("canceled " + errString(errors.Unwrap(cause))) },
+	})
+	gr.AddHooks(rungroup.Hooks{}) // nil callbacks are skipped
+	started := make(chan struct{})
+	gr.Go(func(ctx context.Context) { close(started); <-ctx.Done() })
+	<-started
+	gr.GoCancelOnError(func(context.Context) error { return ErrStop })
+	err := gr.Wait()
+	assertErrorIs(t, err, ErrStop)
+	assertEqual(t, len(events), 5)
+	if len(events) == 5 {
+		assertEqual(t, events[2], "finished stop")
+		assertEqual(t, events[3], "canceled stop")
+		assertEqual(t, events[4], "finished <nil>")
+	}
+}
+
+func TestGroup_AddHooks_remove(t *testing.T) {
+	var gr rungroup.Group
+	defer gr.Close()
+	var first, second int
+	removeFirst := gr.AddHooks(rungroup.Hooks{TaskStarted: func(rungroup.TaskID) { first++ }})
+	gr.AddHooks(rungroup.Hooks{TaskStarted: func(rungroup.TaskID) { second++ }})
+	gr.Go(func(context.Context) {})
+	gr.Wait()
+	removeFirst()
+	removeFirst()
+	gr.Go(func(context.Context) {})
+	gr.Wait()
+	assertEqual(t, first, 1)
+	assertEqual(t, second, 2)
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
@@ -0,0 +1,107 @@
+package rungroup
+
+import (
+	"errors"
+	"time"
+
+	"github.com/goaux/stacktrace/v2"
+)
+
+// ErrIdle is used as the cause when [Group.SetIdleTimeout] cancels a [Group].
+var ErrIdle = errors.New("idle")
+
+// SetIdleTimeout cancels the [Group] with [ErrIdle] once no tasks have been
+// running for the duration d.
+//
+// The idle period starts when SetIdleTimeout is called if no tasks are
+// running, and whenever the last running task finishes. Starting a task ends
+// the idle period, so the timer restarts after the tasks have finished
+// again.
+//
+// A later call replaces the timeout set by the previous one, and restarts the
+// idle period. If d is zero or negative, the idle timeout is removed.
+//
+// Use Cases:
+//
+// Use this for groups that handle connections or requests, which should go
+// away when there has been nothing to do for a while.
+func (gr *Group) SetIdleTimeout(d time.Duration) {
+	ctx := gr.getContext()
+	callers := gr.callers(1)
+	var w *idleWatcher
+	if d > 0 {
+		w = &idleWatcher{signal: make(chan struct{}, 1), stop: make(chan struct{})}
+	}
+	if old := gr.idle.Swap(w); old != nil {
+		close(old.stop)
+	}
+	if w == nil {
+		return
+	}
+	clock := gr.getClock()
+	go func() {
+		for {
+			if gr.active.Load() != 0 {
+				select {
+				case <-w.signal:
+					continue
+				case <-w.stop:
+					return
+				case <-ctx.Done():
+					return
+				}
+			}
+			t := clock.NewTimer(d)
+			select {
+			case <-t.C():
+				select {
+				case <-w.signal:
+					// A task has started since the timer was created.
+					continue
+				case <-w.stop:
+					return
+				default:
+				}
+				if gr.active.Load() == 0 {
+					gr.cancelCause(stacktrace.NewError(ErrIdle, callers))
+					return
+				}
+			case <-w.signal:
+				t.Stop()
+			case <-w.stop:
+				t.Stop()
+				return
+			case <-ctx.Done():
+				t.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// idleWatcher is the watcher goroutine started by [Group.SetIdleTimeout].
+type idleWatcher struct {
+	signal chan struct{} // wakes up the watcher to check whether tasks are running
+	stop   chan struct{} // closed when the watcher is replaced
+}
+
+// deactivate records the end of a task. If no tasks are running anymore, it
+// signals the watcher of [Group.SetIdleTimeout], and checks whether the
+// [Group] is done for the subscribers of [Group.Subscribe].
+func (gr *Group) deactivate() {
+	if gr.active.Add(-1) == 0 {
+		gr.signalIdle()
+		gr.checkDone()
+	}
+}
+
+// signalIdle wakes up the watcher of [Group.SetIdleTimeout], if any, to check
+// whether tasks are running.
+func (gr *Group) signalIdle() {
+	if w := gr.idle.Load(); w != nil {
+		select {
+		case w.signal <- struct{}{}:
+		default:
+		}
+	}
+}
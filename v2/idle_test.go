@@ -0,0 +1,61 @@
+package rungroup_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/rungroup/v2/rungrouptest"
+)
+
+func TestGroup_SetIdleTimeout(t *testing.T) {
+	clock := rungrouptest.NewClock(time.Unix(0, 0))
+	canceled := make(chan error, 1)
+	var gr rungroup.Group
+	defer gr.Close()
+	gr.SetClock(clock)
+	gr.AddHooks(rungroup.Hooks{Canceled: func(cause error) { canceled <- cause }})
+	gr.SetIdleTimeout(time.Minute)
+
+	// A running task ends the idle period.
+	clock.BlockUntil(1)
+	release := make(chan struct{})
+	gr.Go(func(context.Context) { <-release })
+	clock.Advance(time.Minute)
+	close(release)
+	assertNoError(t, gr.Wait())
+	select {
+	case cause := <-canceled:
+		t.Fatalf("canceled while a task was running: %v", cause)
+	default:
+	}
+
+	// The idle period restarts once the task has finished.
+	for {
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+		select {
+		case cause := <-canceled:
+			assertErrorIs(t, cause, rungroup.ErrIdle)
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestGroup_SetIdleTimeout_replace(t *testing.T) {
+	for _, d := range []time.Duration{time.Hour, 0} {
+		canceled := make(chan error, 1)
+		var gr rungroup.Group
+		gr.AddHooks(rungroup.Hooks{Canceled: func(cause error) { canceled <- cause }})
+		gr.SetIdleTimeout(10 * time.Millisecond)
+		gr.SetIdleTimeout(d)
+		select {
+		case cause := <-canceled:
+			t.Errorf("SetIdleTimeout(%v): canceled by the replaced timeout: %v", d, cause)
+		case <-time.After(50 * time.Millisecond):
+		}
+		gr.Close()
+	}
+}
@@ -0,0 +1,129 @@
+package rungroup
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSkipped is reported for the tasks queued by [Group.GoKeyed] that are not
+// started because the [Group] was canceled.
+var ErrSkipped = errors.New("skipped")
+
+// GoKeyed starts a task using [Group.Go], but runs the tasks that share the
+// same key one at a time, in the order in which GoKeyed was called. Tasks with
+// different keys run concurrently.
+//
+// GoKeyed does not block: if a task with the same key is running, the task is
+// queued, and it is started once the tasks queued before it have returned.
+// The state kept for a key is freed once its queue is empty.
+//
+// Once the [Group] is canceled, the queued tasks are not started. They are
+// counted in [Stats].Skipped and reported to [Hooks].TaskRejected with
+// [ErrSkipped].
+//
+// Use Cases:
+//
+// Use this for per-account processing, where the events of an account must be
+// handled in order, while the events of different accounts are handled in
+// parallel.
+func (gr *Group) GoKeyed(key string, task func(context.Context)) {
+	t := keyedTask{callers: gr.taskCallers(CancelNever, 1), fn: task}
+	gr.mu.Lock()
+	if q, ok := gr.keys[key]; ok {
+		q.tasks = append(q.tasks, t)
+		gr.mu.Unlock()
+		return
+	}
+	if gr.keys == nil {
+		gr.keys = map[string]*keyQueue{}
+	}
+	gr.keys[key] = &keyQueue{}
+	gr.mu.Unlock()
+	gr.startKeyed(key, t)
+}
+
+// keyQueue holds the tasks of a key that wait for the running task with that
+// key to return.
+type keyQueue struct {
+	tasks []keyedTask
+}
+
+// keyedTask is a task started by [Group.GoKeyed].
+type keyedTask struct {
+	callers []uintptr
+	fn      func(context.Context)
+}
+
+// startKeyed starts t, the task with the key, and then, once t has returned,
+// the next task queued for the key.
+//
+// If t is not started because the [Group] is draining, the next task is
+// started instead.
+func (gr *Group) startKeyed(key string, t keyedTask) {
+	for {
+		id := gr.goTask(taskSpec{callers: t.callers}, func(ctx context.Context) error {
+			defer gr.nextKeyed(key)
+			t.fn(ctx)
+			return nil
+		})
+		if id != 0 {
+			return
+		}
+		next, ok := gr.popKeyed(key)
+		if !ok {
+			return
+		}
+		t = next
+	}
+}
+
+// nextKeyed starts the next task queued for the key, after the running task
+// with the key returns.
+//
+// It is called by the running task, so that the [Group] does not finish in
+// between, but the next task is started in another goroutine, so that it does
+// not wait for the limits of the [Group] held by the running task.
+func (gr *Group) nextKeyed(key string) {
+	next, ok := gr.popKeyed(key)
+	if !ok {
+		return
+	}
+	gr.g.Add(1)
+	go func() {
+		defer gr.g.Done()
+		gr.startKeyed(key, next)
+	}()
+}
+
+// popKeyed removes the next task queued for the key, and reports whether there
+// is one to start. If the queue is empty, or the [Group] is canceled and the
+// queued tasks are skipped, the state of the key is freed.
+func (gr *Group) popKeyed(key string) (keyedTask, bool) {
+	canceled := gr.getContext().Err() != nil
+	gr.mu.Lock()
+	q := gr.keys[key]
+	if canceled || len(q.tasks) == 0 {
+		delete(gr.keys, key)
+		gr.mu.Unlock()
+		for range q.tasks {
+			gr.skip()
+		}
+		return keyedTask{}, false
+	}
+	t := q.tasks[0]
+	q.tasks[0] = keyedTask{}
+	q.tasks = q.tasks[1:]
+	gr.mu.Unlock()
+	return t, true
+}
+
+// skip reports a task queued by [Group.GoKeyed] that is not started because
+// the [Group] was canceled.
+func (gr *Group) skip() {
+	gr.stats.skipped.Add(1)
+	for _, h := range gr.getHooks() {
+		if h.TaskRejected != nil {
+			h.TaskRejected(ErrSkipped)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestGroup_GoKeyed(t *testing.T) {
+	t.Run("serial per key", func(t *testing.T) {
+		var mu sync.Mutex
+		running := map[string]bool{}
+		got := map[string][]int{}
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetLimit(2)
+		for i := 0; i < 100; i++ {
+			key, i := fmt.Sprint("account", i%3), i
+			gr.GoKeyed(key, func(context.Context) {
+				mu.Lock()
+				if running[key] {
+					t.Errorf("two tasks with key %s are running", key)
+				}
+				running[key] = true
+				got[key] = append(got[key], i)
+				mu.Unlock()
+				runtime.Gosched()
+				mu.Lock()
+				running[key] = false
+				mu.Unlock()
+			})
+		}
+		gr.Wait()
+		for key, seq := range got {
+			for j := 1; j < len(seq); j++ {
+				if seq[j] <= seq[j-1] {
+					t.Errorf("key %s ran out of order: %v", key, seq)
+					break
+				}
+			}
+		}
+		assertEqual(t, gr.Stats().Started, int64(100))
+	})
+
+	t.Run("different keys run concurrently", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		a, b := make(chan struct{}), make(chan struct{})
+		gr.GoKeyed("a", func(context.Context) { close(a); <-b })
+		gr.GoKeyed("b", func(context.Context) { <-a; close(b) })
+		gr.Wait()
+	})
+
+	t.Run("skipped on cancel", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		var mu sync.Mutex
+		var rejected []error
+		var gr rungroup.Group
+		gr.AddHooks(rungroup.Hooks{TaskRejected: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			rejected = append(rejected, err)
+		}})
+		started := make(chan struct{})
+		gr.GoKeyed("a", func(ctx context.Context) { close(started); <-ctx.Done() })
+		<-started
+		for i := 0; i < 3; i++ {
+			gr.GoKeyed("a", func(context.Context) { t.Error("queued task started after the cancellation") })
+		}
+		gr.Cancel(ErrStop)
+		assertErrorIs(t, gr.Wait(), ErrStop)
+		assertEqual(t, gr.Stats().Skipped, int64(3))
+		assertEqual(t, len(rejected), 3)
+		for _, err := range rejected {
+			assertErrorIs(t, err, rungroup.ErrSkipped)
+		}
+
+		// The state of the key is freed, so a new task with the key starts.
+		done := make(chan struct{})
+		gr.GoKeyed("a", func(context.Context) { close(done) })
+		<-done
+	})
+}
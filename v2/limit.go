@@ -0,0 +1,48 @@
+package rungroup
+
+import (
+	"context"
+)
+
+// SetLimit limits the number of active tasks in the [Group] to at most n. A
+// negative value indicates no limit, which is the default.
+//
+// While the limit is reached, [Group.Go] and the other methods that start a
+// task block until a task finishes, whereas [Group.TryGo] returns false.
+// Changing the limit does not affect the tasks that are already active.
+func (gr *Group) SetLimit(n int) {
+	if n < 0 {
+		gr.sem.Store(nil)
+		return
+	}
+	sem := make(chan struct{}, n)
+	gr.sem.Store(&sem)
+}
+
+// TryGo starts a task using [Group.Go] only if the number of active tasks in
+// the [Group] is below the limit set by [Group.SetLimit]. It reports whether
+// the task was started.
+func (gr *Group) TryGo(task func(context.Context)) bool {
+	return gr.goTask(taskSpec{callers: gr.taskCallers(CancelNever, 1), fn: task, try: true}, nil) != 0
+}
+
+// acquire reserves a place for a task within the limit set by SetLimit,
+// waiting for one unless try is set. It returns the function that frees the
+// place, and reports whether the place has been reserved.
+func (gr *Group) acquire(try bool) (release func(), ok bool) {
+	p := gr.sem.Load()
+	if p == nil {
+		return func() {}, true
+	}
+	sem := *p
+	if try {
+		select {
+		case sem <- struct{}{}:
+		default:
+			return nil, false
+		}
+	} else {
+		sem <- struct{}{}
+	}
+	return func() { <-sem }, true
+}
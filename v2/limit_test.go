@@ -0,0 +1,47 @@
+package rungroup_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestGroup_SetLimit(t *testing.T) {
+	t.Run("limit", func(t *testing.T) {
+		active, peak := int32(0), int32(0)
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetLimit(2)
+		for i := 0; i < 20; i++ {
+			gr.Go(func(context.Context) {
+				n := atomic.AddInt32(&active, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				atomic.AddInt32(&active, -1)
+			})
+		}
+		assertNoError(t, gr.Wait())
+		assertEqual(t, peak <= 2, true, "peak=", peak)
+	})
+
+	t.Run("TryGo", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetLimit(1)
+		release := make(chan struct{})
+		assertEqual(t, gr.TryGo(func(context.Context) { <-release }), true)
+		assertEqual(t, gr.TryGo(func(context.Context) {}), false)
+		close(release)
+		gr.Wait()
+		assertEqual(t, gr.TryGo(func(context.Context) {}), true)
+		gr.SetLimit(-1)
+		assertEqual(t, gr.TryGo(func(context.Context) {}), true)
+		assertNoError(t, gr.Wait())
+	})
+}
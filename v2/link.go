@@ -0,0 +1,126 @@
+package rungroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrLinked is matched, with [errors.Is], by the cause with which a [Group] is
+// canceled because a group linked to it was canceled. See [Link].
+var ErrLinked = errors.New("linked group canceled")
+
+// Link links the groups a and b, so that canceling either of them cancels the
+// other one as well.
+//
+// The cause with which the other group is canceled matches both [ErrLinked]
+// and the original cause. If one of the groups is already canceled, Link
+// cancels the other one immediately.
+//
+// Once linked, the cancellation is propagated by the attempts recorded by
+// [Group.CancelHistory]; a later cancellation of the parent context passed to
+// [New] is not propagated. Links may form cycles: a group that is already
+// canceled does not propagate the cancellation again.
+//
+// Use Cases:
+//
+// Use this for peer groups, such as an ingest group and a serve group, that
+// should fail together but have separate lifetimes and separate [Group.Wait]
+// calls.
+func Link(a, b *Group) {
+	LinkOneWay(a, b)
+	LinkOneWay(b, a)
+}
+
+// LinkOneWay links the group from to the group to, so that canceling from
+// cancels to as well, but not the other way around. See [Link].
+func LinkOneWay(from, to *Group) {
+	if from == to {
+		return
+	}
+	gc := from.getGroupContext()
+	from.mu.Lock()
+	canceled := gc.ctx.Err() != nil
+	if !canceled {
+		from.links = append(from.links, to)
+	}
+	from.mu.Unlock()
+	if canceled {
+		to.cancelCause(linkedCause(context.Cause(gc.ctx)))
+	}
+}
+
+// Unlink removes the links between the groups a and b in both directions.
+func Unlink(a, b *Group) {
+	a.unlink(b)
+	b.unlink(a)
+}
+
+// unlink removes the links from gr to the group to.
+func (gr *Group) unlink(to *Group) {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+	links := gr.links[:0]
+	for _, l := range gr.links {
+		if l != to {
+			links = append(links, l)
+		}
+	}
+	gr.links = links
+}
+
+// propagateCancel cancels the groups linked from gr with cause.
+//
+// It is called once, by the attempt that canceled gr.
+func (gr *Group) propagateCancel(cause error) {
+	gr.mu.Lock()
+	links := gr.links
+	gr.links = nil
+	gr.mu.Unlock()
+	if len(links) == 0 {
+		return
+	}
+	err := linkedCause(cause)
+	for _, to := range links {
+		to.cancelCause(err)
+	}
+}
+
+// linkedCause returns the cause for canceling a group linked to a group that
+// was canceled with cause.
+func linkedCause(cause error) error {
+	return fmt.Errorf("%w: %w", ErrLinked, cause)
+}
+
+// WaitAll waits for all of the groups to finish, as [Group.Wait] does, and
+// returns the errors returned by their Wait methods joined with
+// [errors.Join].
+func WaitAll(groups ...*Group) error {
+	errs := make([]error, len(groups))
+	for i, gr := range groups {
+		errs[i] = gr.Wait()
+	}
+	return errors.Join(errs...)
+}
+
+// WaitAny waits for any of the groups to finish, as [Group.Wait] does, and
+// returns the index of that group and the error returned by its Wait method.
+//
+// WaitAny returns -1 and nil if groups is empty. The waits for the other
+// groups continue in the background until they finish.
+func WaitAny(groups ...*Group) (int, error) {
+	if len(groups) == 0 {
+		return -1, nil
+	}
+	type result struct {
+		i   int
+		err error
+	}
+	done := make(chan result, len(groups))
+	for i, gr := range groups {
+		i, gr := i, gr
+		go func() { done <- result{i, gr.Wait()} }()
+	}
+	r := <-done
+	return r.i, r.err
+}
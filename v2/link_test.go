@@ -0,0 +1,97 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestLink(t *testing.T) {
+	ErrStop := errors.New("stop")
+
+	t.Run("both ways", func(t *testing.T) {
+		for _, first := range []int{0, 1} {
+			var groups [2]rungroup.Group
+			rungroup.Link(&groups[0], &groups[1])
+			groups[first].Cancel(ErrStop)
+			for i := range groups {
+				err := groups[i].Wait()
+				assertErrorIs(t, err, ErrStop)
+				assertEqual(t, errors.Is(err, rungroup.ErrLinked), i != first)
+			}
+		}
+	})
+
+	t.Run("one way", func(t *testing.T) {
+		var from, to rungroup.Group
+		rungroup.LinkOneWay(&from, &to)
+		to.Cancel(ErrStop)
+		assertNoError(t, context.Cause(contextOf(&from)))
+		from.Close()
+		assertErrorIs(t, from.Wait(), rungroup.ErrClosed)
+		assertErrorIs(t, to.Wait(), ErrStop)
+	})
+
+	t.Run("already canceled", func(t *testing.T) {
+		var a, b rungroup.Group
+		a.Cancel(ErrStop)
+		rungroup.Link(&a, &b)
+		err := b.Wait()
+		assertErrorIs(t, err, ErrStop)
+		assertErrorIs(t, err, rungroup.ErrLinked)
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		var a, b, c rungroup.Group
+		rungroup.Link(&a, &b)
+		rungroup.Link(&b, &c)
+		rungroup.Link(&c, &a)
+		b.GoCancelOnError(func(context.Context) error { return ErrStop })
+		assertErrorIs(t, rungroup.WaitAll(&a, &b, &c), ErrStop)
+		for _, gr := range []*rungroup.Group{&a, &b, &c} {
+			assertEqual(t, gr.CancelHistory()[0].First, true)
+		}
+	})
+
+	t.Run("unlink", func(t *testing.T) {
+		var a, b rungroup.Group
+		defer b.Close()
+		rungroup.Link(&a, &b)
+		rungroup.Unlink(&a, &b)
+		a.Cancel(ErrStop)
+		assertNoError(t, context.Cause(contextOf(&b)))
+	})
+}
+
+func TestWaitAll(t *testing.T) {
+	ErrStop := errors.New("stop")
+	var a, b rungroup.Group
+	assertNoError(t, rungroup.WaitAll())
+	assertNoError(t, rungroup.WaitAll(&a, &b))
+	b.Cancel(ErrStop)
+	assertErrorIs(t, rungroup.WaitAll(&a, &b), ErrStop)
+}
+
+func TestWaitAny(t *testing.T) {
+	ErrStop := errors.New("stop")
+	i, err := rungroup.WaitAny()
+	assertEqual(t, i, -1)
+	assertNoError(t, err)
+
+	var a, b rungroup.Group
+	defer a.Close()
+	a.Go(func(ctx context.Context) { <-ctx.Done() })
+	b.GoCancelOnError(func(context.Context) error { return ErrStop })
+	i, err = rungroup.WaitAny(&a, &b)
+	assertEqual(t, i, 1)
+	assertErrorIs(t, err, ErrStop)
+}
+
+// contextOf returns the context of the tasks of gr.
+func contextOf(gr *rungroup.Group) context.Context {
+	ch := make(chan context.Context, 1)
+	gr.Go(func(ctx context.Context) { ch <- ctx })
+	return <-ch
+}
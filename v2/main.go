@@ -0,0 +1,109 @@
+package rungroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/goaux/stacktrace/v2"
+)
+
+// ErrSignal is matched, with [errors.Is], by the cause with which [Main]
+// cancels the [Group] when the process receives a signal.
+var ErrSignal = errors.New("signal")
+
+// TimeoutExitCode is the exit code that [Main] returns by default when the
+// [Group] was canceled by a timeout, such as the one set by
+// [Group.SetTimeout]. Use [MainWith] to return another code.
+const TimeoutExitCode = 124
+
+// MainOptions configures [MainWith].
+type MainOptions struct {
+	// TimeoutExitCode is the exit code returned when the [Group] was canceled
+	// by a timeout. It defaults to [TimeoutExitCode].
+	TimeoutExitCode int
+}
+
+// Main runs a [Group] for the main function of a program, and returns the exit
+// code for the process. It is equivalent to [MainWith] with the default
+// options.
+//
+// Main creates a [Group], calls setup with it to start the tasks, and waits for
+// the tasks to finish. An error returned by setup cancels the [Group]. When
+// the process receives an interrupt or SIGTERM, Main cancels the [Group] with
+// a cause that matches [ErrSignal]; a second signal terminates the process as
+// if Main were not handling signals.
+//
+// If the [Group] was canceled, Main prints the error returned by
+// [Group.Wait] to [os.Stderr], formatted with stacktrace.Format. The exit code
+// depends on the cause of the cancellation:
+//
+//   - 1 if a task panicked, whatever the cause of the cancellation.
+//   - 0 if the [Group] was not canceled, or was canceled with [ErrClosed] or
+//     by a signal.
+//   - [TimeoutExitCode] if the [Group] was canceled by a timeout, i.e. the
+//     cause matches [context.DeadlineExceeded].
+//   - 1 otherwise, for example when a task failed.
+//
+// Use Cases:
+//
+// Use this to write the main function of a service in one line:
+//
+//	func main() {
+//		os.Exit(rungroup.Main(run))
+//	}
+func Main(setup func(gr *Group) error) int {
+	return MainWith(MainOptions{}, setup)
+}
+
+// MainWith is like [Main], but configured by opts.
+func MainWith(opts MainOptions, setup func(gr *Group) error) int {
+	if opts.TimeoutExitCode == 0 {
+		opts.TimeoutExitCode = TimeoutExitCode
+	}
+	gr := New(context.Background())
+	defer gr.Close()
+	ctx := gr.getContext()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case s := <-sig:
+			signal.Stop(sig)
+			gr.cancelCause(fmt.Errorf("%w: %v", ErrSignal, s))
+		case <-done:
+		}
+	}()
+
+	if err := setup(gr); err != nil {
+		gr.Cancel(err)
+	}
+	err := gr.Wait()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, stacktrace.Format(err))
+	}
+	if gr.panicked.Load() != nil {
+		return 1
+	}
+	return exitCode(context.Cause(ctx), opts)
+}
+
+// exitCode returns the exit code of [MainWith] for the cause of the
+// cancellation of the [Group].
+func exitCode(cause error, opts MainOptions) int {
+	switch {
+	case cause == nil, errors.Is(cause, ErrClosed), errors.Is(cause, ErrSignal):
+		return 0
+	case errors.Is(cause, context.DeadlineExceeded):
+		return opts.TimeoutExitCode
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,125 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestMain_exitCode(t *testing.T) {
+	ErrStop := errors.New("stop")
+	tests := []struct {
+		name   string
+		setup  func(gr *rungroup.Group) error
+		code   int
+		output string
+	}{
+		{
+			name:  "no tasks",
+			setup: func(gr *rungroup.Group) error { return nil },
+			code:  0,
+		},
+		{
+			name: "closed",
+			setup: func(gr *rungroup.Group) error {
+				gr.Go(func(context.Context) { gr.Close() })
+				return nil
+			},
+			code:   0,
+			output: "closed",
+		},
+		{
+			name:   "setup error",
+			setup:  func(gr *rungroup.Group) error { return ErrStop },
+			code:   1,
+			output: "stop",
+		},
+		{
+			name: "task error",
+			setup: func(gr *rungroup.Group) error {
+				gr.GoCancelOnError(func(context.Context) error { return ErrStop })
+				return nil
+			},
+			code:   1,
+			output: "stop",
+		},
+		{
+			name: "panic after close",
+			setup: func(gr *rungroup.Group) error {
+				gr.Go(func(ctx context.Context) { <-ctx.Done(); panic("boom") })
+				gr.Close()
+				return nil
+			},
+			code:   1,
+			output: "panic: boom",
+		},
+		{
+			name: "timeout",
+			setup: func(gr *rungroup.Group) error {
+				gr.SetTimeout(time.Millisecond)
+				gr.Go(func(ctx context.Context) { <-ctx.Done() })
+				return nil
+			},
+			code:   rungroup.TimeoutExitCode,
+			output: "deadline exceeded",
+		},
+		{
+			name: "signal",
+			setup: func(gr *rungroup.Group) error {
+				p, err := os.FindProcess(os.Getpid())
+				if err == nil {
+					err = p.Signal(os.Interrupt)
+				}
+				if err != nil {
+					t.Skipf("cannot send an interrupt: %v", err)
+				}
+				gr.Go(func(ctx context.Context) { <-ctx.Done() })
+				return nil
+			},
+			code:   0,
+			output: "signal: interrupt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var code int
+			output := captureStderr(t, func() { code = rungroup.Main(tt.setup) })
+			assertEqual(t, code, tt.code)
+			if !strings.Contains(output, tt.output) || (tt.output == "") != (output == "") {
+				t.Errorf("output=%q, expect=%q", output, tt.output)
+			}
+		})
+	}
+}
+
+func TestMainWith(t *testing.T) {
+	var code int
+	captureStderr(t, func() {
+		code = rungroup.MainWith(rungroup.MainOptions{TimeoutExitCode: 3}, func(gr *rungroup.Group) error {
+			gr.SetTimeout(time.Millisecond)
+			gr.Go(func(ctx context.Context) { <-ctx.Done() })
+			return nil
+		})
+	})
+	assertEqual(t, code, 3)
+}
+
+// captureStderr returns what f writes to os.Stderr.
+func captureStderr(t *testing.T, f func()) string {
+	t.Helper()
+	file, err := os.CreateTemp(t.TempDir(), "stderr")
+	assertNoError(t, err)
+	defer file.Close()
+	stderr := os.Stderr
+	os.Stderr = file
+	defer func() { os.Stderr = stderr }()
+	f()
+	b, err := os.ReadFile(file.Name())
+	assertNoError(t, err)
+	return string(b)
+}
@@ -0,0 +1,66 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestGroup_SetPool(t *testing.T) {
+	t.Run("tasks", func(t *testing.T) {
+		n := int32(0)
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetPool(time.Second)
+		for i := 0; i < 1000; i++ {
+			gr.Go(func(context.Context) { atomic.AddInt32(&n, 1) })
+		}
+		assertNoError(t, gr.Wait())
+		assertEqual(t, n, 1000)
+	})
+
+	t.Run("waiting on a child task", func(t *testing.T) {
+		n := int32(0)
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetPool(time.Second)
+		for i := 0; i < 100; i++ {
+			gr.Go(func(context.Context) {
+				done := make(chan struct{})
+				gr.Go(func(context.Context) { atomic.AddInt32(&n, 1); close(done) })
+				<-done
+				atomic.AddInt32(&n, 1)
+			})
+		}
+		assertNoError(t, gr.Wait())
+		assertEqual(t, n, 200)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetPool(time.Second)
+		gr.Go(func(ctx context.Context) { <-ctx.Done() })
+		gr.GoCancelOnError(func(context.Context) error { return ErrStop })
+		assertErrorIs(t, gr.Wait(), ErrStop)
+		// Tasks started after the cancellation still run.
+		ran := false
+		gr.Go(func(context.Context) { ran = true })
+		gr.Wait()
+		assertEqual(t, ran, true)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetPool(time.Second)
+		gr.SetPool(0)
+		gr.Go(func(context.Context) {})
+		assertNoError(t, gr.Wait())
+	})
+}
@@ -0,0 +1,193 @@
+package rungroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/goaux/stacktrace/v2"
+)
+
+// ErrNotReady is matched, with [errors.Is], by the error that
+// [Group.WaitReady] returns when a task started with [Group.GoReady] returned
+// without signalling readiness.
+var ErrNotReady = errors.New("task returned without signalling readiness")
+
+// GoReady starts a task using [Group.Go] and marks it as needing readiness.
+//
+// The task receives a ready function, which it calls once it is ready to do
+// its work, for example after a cache has been warmed up. [Group.WaitReady]
+// blocks until every task started with GoReady has called ready.
+//
+// Calling ready more than once has no effect. A task that returns without
+// calling ready makes WaitReady fail with an error that matches
+// [ErrNotReady], and a task that panics cancels the [Group], so that
+// WaitReady returns the [*PanicError]. A task that is not started because the
+// [Group] is draining is treated as ready.
+func (gr *Group) GoReady(task func(ctx context.Context, ready func())) {
+	gr.goReadyTask(gr.taskCallers(CancelNever, 1), task)
+}
+
+// goReadyTask implements [Group.GoReady] for a task started at callers.
+func (gr *Group) goReadyTask(callers []uintptr, task func(ctx context.Context, ready func())) {
+	gr.goReady(callers, func(ctx context.Context, ready func()) error {
+		task(ctx, ready)
+		return stacktrace.NewError(ErrNotReady, callers)
+	})
+}
+
+// goReady starts a task at callers that must signal readiness. If the task
+// returns without calling ready, the error it returns is reported by
+// [Group.WaitReady].
+func (gr *Group) goReady(callers []uintptr, task func(ctx context.Context, ready func()) error) {
+	ready := gr.addNotReady()
+	id := gr.goTask(taskSpec{callers: callers}, func(ctx context.Context) error {
+		signalled := false
+		err := task(ctx, func() { signalled = true; ready() })
+		if !signalled {
+			gr.notReadyErr(err)
+		}
+		ready()
+		return nil
+	})
+	if id == 0 {
+		// The task was rejected, and will never signal readiness.
+		ready()
+	}
+}
+
+// WaitReady blocks until every task started with [Group.GoReady] has signalled
+// readiness, and then returns nil.
+//
+// If the [Group] is canceled first, WaitReady returns the cause of the
+// cancellation of the [Group]. If ctx is done first, WaitReady returns the
+// cause of ctx. If a task returned without signalling readiness, WaitReady
+// returns an error that matches [ErrNotReady].
+func (gr *Group) WaitReady(ctx context.Context) error {
+	grctx := gr.getContext()
+	gr.mu.Lock()
+	ch := gr.readyCh
+	gr.mu.Unlock()
+	if ch != nil {
+		select {
+		case <-ch:
+		case <-grctx.Done():
+			return context.Cause(grctx)
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+	if grctx.Err() != nil {
+		return context.Cause(grctx)
+	}
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+	return gr.readyErr
+}
+
+// notReadyErr records err, the reason why a task returned without signalling
+// readiness, unless a reason has already been recorded.
+func (gr *Group) notReadyErr(err error) {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+	if gr.readyErr == nil {
+		gr.readyErr = err
+	}
+}
+
+// addNotReady registers a task that has not signalled readiness yet, and
+// returns the function that signals it.
+func (gr *Group) addNotReady() func() {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+	if gr.notReady == 0 {
+		gr.readyCh = make(chan struct{})
+	}
+	gr.notReady++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			gr.mu.Lock()
+			defer gr.mu.Unlock()
+			gr.notReady--
+			if gr.notReady == 0 {
+				close(gr.readyCh)
+				gr.readyCh = nil
+			}
+		})
+	}
+}
+
+// A Stage is a set of tasks that [Group.GoStages] starts together.
+type Stage []func(ctx context.Context, ready func())
+
+// GoStages starts stages of tasks in order, and shuts them down in reverse
+// order.
+//
+// The tasks of a stage are started as if by [Group.GoReady]. Stage N+1 is
+// started only after every task of stage N has signalled readiness, and
+// [Group.WaitReady] waits for the last stage. If the [Group] is canceled during
+// startup, or a task of a stage returns without signalling readiness, the
+// remaining stages are not started, the started ones are shut down, and
+// WaitReady fails.
+//
+// Each stage has its own context, which carries the values of the [Group]'s
+// context but is not canceled together with it. When the [Group] is canceled,
+// the stages are canceled one at a time, starting with the last one, and the
+// next stage is canceled only after all tasks of the previous one have
+// returned. The cause of the cancellation of the [Group] is passed on to each
+// stage. Conversely, when a stage is canceled, for example because one of its
+// tasks panics, the [Group] is canceled with a cause that matches both
+// [ErrLinked] and the cause of the stage.
+//
+// Use Cases:
+//
+// Use this when tasks depend on each other being up, for example when an HTTP
+// server must not accept requests before its cache has been warmed up, and
+// must stop accepting requests before the cache is torn down.
+func (gr *Group) GoStages(stages ...Stage) {
+	callers := gr.taskCallers(CancelNever, 1)
+	gr.goReady(callers, func(ctx context.Context, ready func()) error {
+		parent := withoutCancel{ctx}
+		started := make([]*Group, 0, len(stages))
+		var cause error // cause of a failed startup
+		defer func() {
+			if cause == nil {
+				cause = context.Cause(ctx)
+			}
+			for i := len(started) - 1; i >= 0; i-- {
+				started[i].unlink(gr)
+				started[i].cancelCause(cause)
+				started[i].Wait()
+			}
+		}()
+		for _, stage := range stages {
+			sg := New(parent)
+			started = append(started, sg)
+			LinkOneWay(sg, gr)
+			for _, task := range stage {
+				sg.goReadyTask(callers, task)
+			}
+			if err := sg.WaitReady(ctx); err != nil {
+				cause = err
+				return err
+			}
+		}
+		ready()
+		<-ctx.Done()
+		return nil
+	})
+}
+
+// withoutCancel is a context that carries the values of its parent, but is
+// never canceled.
+type withoutCancel struct {
+	context.Context
+}
+
+func (withoutCancel) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (withoutCancel) Done() <-chan struct{} { return nil }
+
+func (withoutCancel) Err() error { return nil }
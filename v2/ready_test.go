@@ -0,0 +1,199 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func ExampleGroup_GoStages() {
+	var mu sync.Mutex
+	var log []string
+	record := func(s string) { mu.Lock(); defer mu.Unlock(); log = append(log, s) }
+
+	var gr rungroup.Group
+	defer gr.Close()
+	gr.GoStages(
+		rungroup.Stage{func(ctx context.Context, ready func()) {
+			record("cache started")
+			ready()
+			<-ctx.Done()
+			record("cache stopped")
+		}},
+		rungroup.Stage{func(ctx context.Context, ready func()) {
+			record("server started")
+			ready()
+			<-ctx.Done()
+			record("server stopped")
+		}},
+	)
+	gr.Go(func(ctx context.Context) {
+		gr.WaitReady(ctx)
+		gr.Close()
+	})
+	gr.Wait()
+	for _, s := range log {
+		fmt.Println(s)
+	}
+	// Output:
+	// cache started
+	// server started
+	// server stopped
+	// cache stopped
+}
+
+func TestGroup_WaitReady(t *testing.T) {
+	t.Run("no tasks", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		assertNoError(t, gr.WaitReady(context.TODO()))
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		release := make(chan struct{})
+		gr.GoReady(func(ctx context.Context, ready func()) { <-release; ready(); ready(); <-ctx.Done() })
+		gr.GoReady(func(ctx context.Context, ready func()) { ready() })
+		close(release)
+		assertNoError(t, gr.WaitReady(context.TODO()))
+		gr.Close()
+		assertErrorIs(t, gr.Wait(), rungroup.ErrClosed)
+	})
+
+	t.Run("returns without ready", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.GoReady(func(ctx context.Context, ready func()) {})
+		assertErrorIs(t, gr.WaitReady(context.TODO()), rungroup.ErrNotReady)
+	})
+
+	t.Run("canceled before ready", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.GoReady(func(ctx context.Context, ready func()) { <-ctx.Done() })
+		gr.Cancel(ErrStop)
+		gr.Wait()
+		assertErrorIs(t, gr.WaitReady(context.TODO()), ErrStop)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.GoReady(func(ctx context.Context, ready func()) { <-ctx.Done() })
+		gr.Cancel(ErrStop)
+		assertErrorIs(t, gr.WaitReady(context.TODO()), ErrStop)
+		gr.Wait()
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.GoReady(func(ctx context.Context, ready func()) { panic("boom") })
+		var pe *rungroup.PanicError
+		assertEqual(t, errors.As(gr.WaitReady(context.TODO()), &pe), true)
+		gr.Wait()
+	})
+
+	t.Run("draining", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		release := make(chan struct{})
+		gr.Go(func(context.Context) { <-release })
+		drained := make(chan error)
+		go func() { drained <- gr.Drain(context.TODO()) }()
+		for gr.TryGo(func(context.Context) {}) {
+			// until Drain has started
+		}
+		gr.GoReady(func(ctx context.Context, ready func()) { t.Error("must not run") })
+		assertNoError(t, gr.WaitReady(context.TODO()))
+		close(release)
+		assertNoError(t, <-drained)
+	})
+
+	t.Run("ctx done", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.GoReady(func(ctx context.Context, ready func()) { <-ctx.Done() })
+		ctx, cancel := context.WithCancelCause(context.TODO())
+		cancel(ErrStop)
+		assertErrorIs(t, gr.WaitReady(ctx), ErrStop)
+	})
+}
+
+func TestGroup_GoStages(t *testing.T) {
+	t.Run("canceled during startup", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		started := false
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.GoStages(
+			rungroup.Stage{func(ctx context.Context, ready func()) {
+				gr.Cancel(ErrStop)
+				<-ctx.Done()
+				assertErrorIs(t, context.Cause(ctx), ErrStop)
+			}},
+			rungroup.Stage{func(ctx context.Context, ready func()) { started = true }},
+		)
+		assertErrorIs(t, gr.Wait(), ErrStop)
+		assertEqual(t, started, false)
+	})
+
+	t.Run("stage not ready", func(t *testing.T) {
+		started := false
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.GoStages(
+			rungroup.Stage{func(ctx context.Context, ready func()) {}},
+			rungroup.Stage{func(ctx context.Context, ready func()) { started = true }},
+		)
+		assertErrorIs(t, gr.WaitReady(context.TODO()), rungroup.ErrNotReady)
+		gr.Wait()
+		assertEqual(t, started, false)
+	})
+
+	t.Run("panic during startup", func(t *testing.T) {
+		started := false
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.GoStages(
+			rungroup.Stage{func(ctx context.Context, ready func()) { panic("boom") }},
+			rungroup.Stage{func(ctx context.Context, ready func()) { started = true }},
+		)
+		var pe *rungroup.PanicError
+		assertErrorIs(t, gr.WaitReady(context.TODO()), rungroup.ErrLinked)
+		err := gr.Wait()
+		assertErrorIs(t, err, rungroup.ErrLinked)
+		assertEqual(t, errors.As(err, &pe), true)
+		assertEqual(t, pe.Value, any("boom"))
+		assertEqual(t, started, false)
+	})
+
+	t.Run("panic after startup", func(t *testing.T) {
+		stopped := false
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.GoStages(
+			rungroup.Stage{func(ctx context.Context, ready func()) {
+				ready()
+				<-ctx.Done()
+				stopped = true
+			}},
+			rungroup.Stage{func(ctx context.Context, ready func()) {
+				ready()
+				panic("boom")
+			}},
+		)
+		var pe *rungroup.PanicError
+		assertEqual(t, errors.As(gr.Wait(), &pe), true)
+		assertEqual(t, stopped, true)
+		assertEqual(t, len(gr.CancelHistory()), 1)
+	})
+}
@@ -0,0 +1,205 @@
+package rungroup
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// registry holds the live groups once [EnableRegistry] has been called.
+var registry struct {
+	enabled atomic.Bool
+	mu      sync.Mutex
+	groups  map[*Group]struct{}
+}
+
+// EnableRegistry makes every [Group] created after the call register itself in
+// a process-wide registry, which [Groups] reports.
+//
+// A registered [Group] keeps track of its running tasks, and is removed from
+// the registry once it has been canceled and all of its tasks have returned.
+// There is no way to disable the registry again.
+//
+// Use Cases:
+//
+// Use this to debug a running process, for example by serving [Groups] over
+// HTTP, as the rungroupdebug package does.
+func EnableRegistry() {
+	registry.enabled.Store(true)
+}
+
+// A GroupInfo describes a live [Group] in the tree returned by [Groups].
+type GroupInfo struct {
+	// CallSite is where the [Group] was created with [New], as
+	// "file.go:line function". It is empty for a zero-value [Group].
+	CallSite string        `json:"call_site,omitempty"`
+	Created  time.Time     `json:"created"`
+	Age      time.Duration `json:"age"`
+
+	// Canceled reports whether the [Group] has been canceled, and Cause is
+	// the error message of the cause of the cancellation.
+	Canceled bool   `json:"canceled"`
+	Cause    string `json:"cause,omitempty"`
+
+	// Tasks are the running tasks of the [Group], in the order they were
+	// started.
+	Tasks []TaskInfo `json:"tasks"`
+}
+
+// A TaskInfo describes a running task in the tree returned by [Groups].
+type TaskInfo struct {
+	ID       TaskID        `json:"id"`
+	Name     string        `json:"name,omitempty"`
+	CallSite string        `json:"call_site"`
+	Started  time.Time     `json:"started"`
+	Age      time.Duration `json:"age"`
+
+	// Groups are the live groups created with [New] from the context of the
+	// task.
+	Groups []GroupInfo `json:"groups,omitempty"`
+}
+
+// Groups returns the tree of the live groups in the registry enabled by
+// [EnableRegistry].
+//
+// The roots of the tree are the groups that were not created from the
+// context of a running task of another registered [Group], for example
+// because that task has returned. The other groups appear under the task from
+// whose context they were created.
+func Groups() []GroupInfo {
+	registry.mu.Lock()
+	groups := make([]*Group, 0, len(registry.groups))
+	for gr := range registry.groups {
+		groups = append(groups, gr)
+	}
+	registry.mu.Unlock()
+
+	live := groups[:0]
+	running := map[TaskID]bool{}
+	for _, gr := range groups {
+		if gr.unregisterIfDone() {
+			continue
+		}
+		live = append(live, gr)
+		gr.debug.Load().tasks.Range(func(key, _ any) bool {
+			running[key.(TaskID)] = true
+			return true
+		})
+	}
+	// Parents were created before their children.
+	sort.Slice(live, func(i, j int) bool {
+		return live[i].debug.Load().created.Before(live[j].debug.Load().created)
+	})
+	children := map[TaskID][]*Group{}
+	var roots []*Group
+	for _, gr := range live {
+		d := gr.debug.Load()
+		if running[d.parent] {
+			children[d.parent] = append(children[d.parent], gr)
+		} else {
+			roots = append(roots, gr)
+		}
+	}
+	now := time.Now()
+	placed := map[*Group]bool{}
+	infos := groupInfos(roots, children, placed, now)
+	for _, gr := range live {
+		// The parent task returned after running was collected.
+		if !placed[gr] {
+			infos = append(infos, groupInfos([]*Group{gr}, children, placed, now)...)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Created.Before(infos[j].Created) })
+	return infos
+}
+
+// groupInfos returns the GroupInfo values of groups and their descendants,
+// and adds them to placed.
+func groupInfos(groups []*Group, children map[TaskID][]*Group, placed map[*Group]bool, now time.Time) []GroupInfo {
+	infos := make([]GroupInfo, 0, len(groups))
+	for _, gr := range groups {
+		if placed[gr] {
+			continue
+		}
+		placed[gr] = true
+		d := gr.debug.Load()
+		ctx := gr.getContext()
+		info := GroupInfo{
+			CallSite: callSite(d.callers),
+			Created:  d.created,
+			Age:      now.Sub(d.created),
+			Canceled: ctx.Err() != nil,
+			Tasks:    []TaskInfo{},
+		}
+		if info.Canceled {
+			info.Cause = context.Cause(ctx).Error()
+		}
+		d.tasks.Range(func(key, value any) bool {
+			id, t := key.(TaskID), value.(*liveTask)
+			info.Tasks = append(info.Tasks, TaskInfo{
+				ID:       id,
+				Name:     t.name,
+				CallSite: callSite(t.callers),
+				Started:  t.start,
+				Age:      now.Sub(t.start),
+				Groups:   groupInfos(children[id], children, placed, now),
+			})
+			return true
+		})
+		sort.Slice(info.Tasks, func(i, j int) bool { return info.Tasks[i].ID < info.Tasks[j].ID })
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Created.Before(infos[j].Created) })
+	return infos
+}
+
+// debugState holds what a registered [Group] keeps for [Groups].
+type debugState struct {
+	callers []uintptr // call site of New
+	created time.Time
+	parent  TaskID   // task from whose context the Group was created, or zero
+	tasks   sync.Map // map[TaskID]*liveTask
+}
+
+// liveTask describes a running task of a registered [Group].
+type liveTask struct {
+	name    string
+	callers []uintptr
+	start   time.Time
+}
+
+// register adds gr to the registry if it is enabled. parent is the parent
+// context of gr, and callers is the call site of [New].
+func (gr *Group) register(parent context.Context, callers []uintptr) {
+	if !registry.enabled.Load() {
+		return
+	}
+	d := &debugState{callers: callers, created: time.Now()}
+	if _, id, ok := FromContext(parent); ok {
+		d.parent = id
+	}
+	gr.debug.Store(d)
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if registry.groups == nil {
+		registry.groups = map[*Group]struct{}{}
+	}
+	registry.groups[gr] = struct{}{}
+}
+
+// unregisterIfDone removes gr from the registry if it has been canceled and
+// has no running tasks. It reports whether gr has been removed.
+func (gr *Group) unregisterIfDone() bool {
+	if gr.debug.Load() == nil {
+		return false
+	}
+	if gr.getContext().Err() == nil || gr.stats.started.Load() != gr.stats.finished.Load() {
+		return false
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	delete(registry.groups, gr)
+	return true
+}
@@ -0,0 +1,138 @@
+package rungroup
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Report describes how the tasks of a [Group] ran.
+//
+// A Report marshals to JSON, so it can be stored along with logs.
+type Report struct {
+	// Cause is the cause of the cancellation of the [Group], or nil if the
+	// [Group] has not been canceled.
+	Cause error `json:"-"`
+
+	// CauseText is the error message of Cause.
+	CauseText string `json:"cause,omitempty"`
+
+	// Tasks describes the finished tasks in the order they were started.
+	Tasks []TaskReport `json:"tasks"`
+}
+
+// A TaskReport describes how a task ran.
+type TaskReport struct {
+	ID       TaskID    `json:"id"`
+	Name     string    `json:"name,omitempty"`
+	CallSite string    `json:"call_site"` // where the task was started, as "file.go:line function"
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Outcome  Outcome   `json:"outcome"`
+
+	// Err is the error returned by the task, or a [*PanicError] if the task
+	// panicked.
+	Err error `json:"-"`
+
+	// ErrText is the error message of Err.
+	ErrText string `json:"error,omitempty"`
+
+	// Triggered reports whether the completion of the task canceled the
+	// [Group].
+	Triggered bool `json:"triggered,omitempty"`
+}
+
+// An Outcome is how a task ended.
+type Outcome string
+
+const (
+	OutcomeOK       Outcome = "ok"       // The task returned a nil error.
+	OutcomeError    Outcome = "error"    // The task returned a non-nil error.
+	OutcomePanic    Outcome = "panic"    // The task panicked.
+	OutcomeCanceled Outcome = "canceled" // The Group was canceled while the task was running.
+)
+
+// outcomeOf returns the Outcome of a task.
+func outcomeOf(err error, panicked, canceledWhileRunning bool) Outcome {
+	switch {
+	case panicked:
+		return OutcomePanic
+	case canceledWhileRunning:
+		return OutcomeCanceled
+	case err != nil:
+		return OutcomeError
+	}
+	return OutcomeOK
+}
+
+// EnableReport makes the [Group] record every task for [Group.Report].
+//
+// Recording keeps a [TaskReport] in memory for each task, so it is meant for
+// groups that run a bounded number of tasks, such as batch jobs. Only tasks
+// that finish after EnableReport returns are recorded.
+func (gr *Group) EnableReport() {
+	gr.report.CompareAndSwap(nil, &report{})
+}
+
+// Report returns a [Report] of the tasks of the [Group].
+//
+// Report is meant to be called after [Group.Wait] returns. Tasks that are still
+// running are not included. If [Group.EnableReport] has not been called, the
+// Tasks of the returned Report is empty.
+func (gr *Group) Report() Report {
+	ctx := gr.getContext()
+	rep := Report{Tasks: []TaskReport{}}
+	if ctx.Err() != nil {
+		rep.Cause = context.Cause(ctx)
+		rep.CauseText = rep.Cause.Error()
+	}
+	if r := gr.report.Load(); r != nil {
+		rep.Tasks = r.get()
+	}
+	return rep
+}
+
+// report collects TaskReport values.
+type report struct {
+	mu    sync.Mutex
+	tasks []TaskReport
+}
+
+func (r *report) add(t TaskReport) {
+	if t.Err != nil {
+		t.ErrText = t.Err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tasks = append(r.tasks, t)
+}
+
+func (r *report) get() []TaskReport {
+	r.mu.Lock()
+	tasks := append([]TaskReport{}, r.tasks...)
+	r.mu.Unlock()
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks
+}
+
+// callSite formats the first frame of callers as "file.go:line function", in
+// the same way as the error messages of stacktrace.
+func callSite(callers []uintptr) string {
+	if len(callers) == 0 {
+		return ""
+	}
+	frame, _ := runtime.CallersFrames(callers[:1]).Next()
+	fn := frame.Function
+	if i := strings.LastIndexByte(fn, '/'); i != -1 {
+		fn = fn[i+1:]
+	}
+	if i := strings.IndexByte(fn, '.'); i != -1 {
+		fn = fn[i+1:]
+	}
+	return fmt.Sprintf("%s:%d %s", filepath.Base(frame.File), frame.Line, fn)
+}
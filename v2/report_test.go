@@ -0,0 +1,80 @@
+package rungroup_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/rungroup/v2/rungrouptest"
+)
+
+func ExampleGroup_Report() {
+	var gr rungroup.Group
+	defer gr.Close()
+	gr.EnableReport()
+	gr.GoNamed("worker", rungroup.CancelOnError, func(ctx context.Context) error {
+		return errors.New("failed")
+	})
+	gr.Wait()
+	rep := gr.Report()
+	fmt.Println(rep.Cause)
+	for _, task := range rep.Tasks {
+		fmt.Println(task.Name, task.CallSite, task.Outcome, task.Err, task.Triggered)
+	}
+	// Output:
+	// failed (report_test.go:19 ExampleGroup_Report)
+	// worker report_test.go:19 ExampleGroup_Report error failed true
+}
+
+func TestGroup_Report(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		var gr rungroup.Group
+		gr.Go(func(context.Context) {})
+		assertNoError(t, gr.Wait())
+		rep := gr.Report()
+		assertNoError(t, rep.Cause)
+		assertEqual(t, len(rep.Tasks), 0)
+		gr.Close()
+	})
+
+	t.Run("outcomes", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetClock(rungrouptest.NewClock(start))
+		gr.EnableReport()
+		gr.Go(func(context.Context) {})
+		gr.Wait()
+		started := make(chan struct{})
+		gr.Go(func(ctx context.Context) { close(started); <-ctx.Done() })
+		<-started
+		gr.GoCancelOnError(func(context.Context) error { return ErrStop })
+		gr.Wait()
+		gr.Go(func(context.Context) { panic("boom") })
+		gr.Wait()
+
+		rep := gr.Report()
+		assertErrorIs(t, rep.Cause, ErrStop)
+		if len(rep.Tasks) != 4 {
+			t.Fatalf("len(Tasks)=%d, want 4", len(rep.Tasks))
+		}
+		assertEqual(t, rep.Tasks[0].Outcome, rungroup.OutcomeOK)
+		assertEqual(t, rep.Tasks[1].Outcome, rungroup.OutcomeCanceled)
+		assertEqual(t, rep.Tasks[2].Outcome, rungroup.OutcomeError)
+		assertEqual(t, rep.Tasks[2].Triggered, true)
+		assertEqual(t, rep.Tasks[3].Outcome, rungroup.OutcomePanic)
+		assertEqual(t, rep.Tasks[3].Triggered, false)
+
+		b, err := json.Marshal(rep.Tasks[2])
+		assertNoError(t, err)
+		want := fmt.Sprintf(`{"id":%d,"call_site":"report_test.go:56 TestGroup_Report.func2",`+
+			`"start":"2024-01-01T00:00:00Z","end":"2024-01-01T00:00:00Z",`+
+			`"outcome":"error","error":"stop","triggered":true}`, rep.Tasks[2].ID)
+		assertEqual(t, string(b), want)
+	})
+}
@@ -0,0 +1,77 @@
+// Package rungroupdebug serves the tree of live [rungroup.Group] values over
+// HTTP.
+//
+// It is the rungroup equivalent of /debug/pprof/goroutine, but with
+// structure: every live group is listed with its running tasks, and the
+// groups created from the context of a task are listed under that task.
+//
+// To use it, mount the handler before creating the groups to inspect:
+//
+//	http.Handle("/debug/rungroup", rungroupdebug.Handler())
+package rungroupdebug
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+// Handler returns an HTTP handler that serves the tree returned by
+// [rungroup.Groups].
+//
+// The tree is served as HTML, or as JSON if the request has the query
+// parameter format=json. Handler calls [rungroup.EnableRegistry], so only the
+// groups created after the call to Handler are served.
+func Handler() http.Handler {
+	rungroup.EnableRegistry()
+	return http.HandlerFunc(serve)
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	groups := rungroup.Groups()
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(groups)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Execute(w, groups); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var page = template.Must(template.New("page").Funcs(template.FuncMap{
+	"age": func(d time.Duration) string { return d.Round(time.Millisecond).String() },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>/debug/rungroup</title>
+<style>
+body { font-family: monospace; }
+ul { list-style: none; padding-left: 2em; }
+.canceled { color: #a00; }
+</style>
+</head>
+<body>
+<p>{{len .}} root groups. <a href="?format=json">json</a></p>
+{{template "groups" .}}
+</body>
+</html>
+{{define "groups"}}<ul>
+{{- range .}}
+<li>group {{with .CallSite}}{{.}}{{else}}(zero value){{end}}, age {{age .Age}}
+{{- if .Canceled}} <span class="canceled">canceled: {{.Cause}}</span>{{end}}
+<ul>
+{{- range .Tasks}}
+<li>task {{.ID}}{{with .Name}} "{{.}}"{{end}} {{.CallSite}}, age {{age .Age}}
+{{- if .Groups}}{{template "groups" .Groups}}{{end}}</li>
+{{- end}}
+</ul></li>
+{{- end}}
+</ul>{{end}}
+`))
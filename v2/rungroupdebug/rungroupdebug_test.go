@@ -0,0 +1,99 @@
+package rungroupdebug_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/rungroup/v2/rungroupdebug"
+)
+
+func TestHandler(t *testing.T) {
+	h := rungroupdebug.Handler()
+
+	outer := rungroup.New(context.Background())
+	defer outer.Close()
+	ready := make(chan struct{})
+	outer.GoNamed("parent", rungroup.CancelNever, func(ctx context.Context) error {
+		inner := rungroup.New(ctx)
+		defer inner.Close()
+		inner.GoNamed("child", rungroup.CancelNever, func(ctx context.Context) error {
+			close(ready)
+			<-ctx.Done()
+			return nil
+		})
+		return inner.Wait()
+	})
+	<-ready
+
+	t.Run("json", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/rungroup?format=json", nil))
+		var groups []rungroup.GroupInfo
+		if err := json.Unmarshal(rec.Body.Bytes(), &groups); err != nil {
+			t.Fatal(err)
+		}
+		if len(groups) != 1 {
+			t.Fatalf("len(groups)=%d, want 1: %s", len(groups), rec.Body)
+		}
+		g := groups[0]
+		if !strings.HasPrefix(g.CallSite, "rungroupdebug_test.go:") || len(g.Tasks) != 1 {
+			t.Fatalf("unexpected group: %+v", g)
+		}
+		task := g.Tasks[0]
+		if task.Name != "parent" || len(task.Groups) != 1 || len(task.Groups[0].Tasks) != 1 {
+			t.Fatalf("unexpected task: %+v", task)
+		}
+		if name := task.Groups[0].Tasks[0].Name; name != "child" {
+			t.Errorf("name=%q, want child", name)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/rungroup", nil))
+		body := rec.Body.String()
+		for _, s := range []string{`"parent"`, `"child"`, "1 root groups"} {
+			if !strings.Contains(body, s) {
+				t.Errorf("body does not contain %q:\n%s", s, body)
+			}
+		}
+	})
+
+	outer.Close()
+	outer.Wait()
+	if groups := rungroup.Groups(); len(groups) != 0 {
+		t.Errorf("finished groups must be removed: %+v", groups)
+	}
+}
+
+func TestHandler_parentReturned(t *testing.T) {
+	h := rungroupdebug.Handler()
+
+	outer := rungroup.New(context.Background())
+	defer outer.Close()
+	inner := make(chan *rungroup.Group, 1)
+	outer.Go(func(ctx context.Context) { inner <- rungroup.New(ctx) })
+	child := <-inner
+	defer child.Close()
+	outer.Wait() // wait for the parent task to return
+	child.Go(func(ctx context.Context) { <-ctx.Done() })
+	outer.Go(func(ctx context.Context) { <-ctx.Done() })
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/rungroup?format=json", nil))
+	var groups []rungroup.GroupInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &groups); err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 {
+		t.Errorf("len(groups)=%d, want 2: %s", len(groups), rec.Body)
+	}
+	outer.Close()
+	outer.Wait()
+	child.Close()
+	child.Wait()
+}
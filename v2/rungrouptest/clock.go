@@ -0,0 +1,99 @@
+package rungrouptest
+
+import (
+	"sync"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+// Clock is a fake [rungroup.Clock] whose time only moves when [Clock.Advance]
+// is called.
+//
+// Install it with [rungroup.Group.SetClock] before the [rungroup.Group] is
+// used.
+type Clock struct {
+	mu     sync.Mutex
+	cond   sync.Cond
+	now    time.Time
+	timers []*timer
+}
+
+// NewClock returns a Clock whose current time is now.
+func NewClock(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond.L = &c.mu
+	return c
+}
+
+// Now returns the current time of the Clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates a timer that fires once the Clock has been advanced by d.
+func (c *Clock) NewTimer(d time.Duration) rungroup.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &timer{clock: c, when: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.ch <- c.now
+		return t
+	}
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	return t
+}
+
+// Advance moves the current time of the Clock forward by d, and fires the
+// timers that expire on the way.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.when.After(c.now) {
+			pending = append(pending, t)
+		} else {
+			t.ch <- c.now
+		}
+	}
+	c.timers = pending
+}
+
+// BlockUntil blocks until at least n timers are waiting to fire.
+//
+// Use it to make sure that a timer created by another goroutine exists before
+// calling [Clock.Advance].
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// timer is a [rungroup.Timer] created by a [Clock].
+type timer struct {
+	clock *Clock
+	when  time.Time
+	ch    chan time.Time
+}
+
+func (t *timer) C() <-chan time.Time { return t.ch }
+
+func (t *timer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, u := range c.timers {
+		if u == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
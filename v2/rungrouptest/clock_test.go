@@ -0,0 +1,62 @@
+package rungrouptest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/rungroup/v2/rungrouptest"
+)
+
+func TestClock(t *testing.T) {
+	t.Run("SetTimeout", func(t *testing.T) {
+		clock := rungrouptest.NewClock(time.Unix(0, 0))
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetClock(clock)
+		gr.SetTimeout(time.Hour)
+		done := make(chan struct{})
+		gr.Go(func(ctx context.Context) { <-ctx.Done(); close(done) })
+		clock.Advance(time.Hour - time.Nanosecond)
+		select {
+		case <-done:
+			t.Fatal("canceled before the timeout")
+		case <-time.After(10 * time.Millisecond):
+		}
+		clock.Advance(time.Nanosecond)
+		err := rungrouptest.AssertFinishedWithin(t, &gr, time.Second)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("err=%v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		clock := rungrouptest.NewClock(time.Unix(0, 0))
+		timer := clock.NewTimer(time.Second)
+		if !timer.Stop() {
+			t.Error("Stop must return true for a pending timer")
+		}
+		if timer.Stop() {
+			t.Error("Stop must return false for a stopped timer")
+		}
+		clock.Advance(time.Second)
+		select {
+		case <-timer.C():
+			t.Error("a stopped timer must not fire")
+		default:
+		}
+	})
+
+	t.Run("BlockUntil", func(t *testing.T) {
+		clock := rungrouptest.NewClock(time.Unix(0, 0))
+		fired := make(chan time.Time)
+		go func() { fired <- <-clock.NewTimer(time.Minute).C() }()
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+		if got, want := <-fired, time.Unix(60, 0); !got.Equal(want) {
+			t.Errorf("fired at %v, want %v", got, want)
+		}
+	})
+}
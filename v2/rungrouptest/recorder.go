@@ -0,0 +1,98 @@
+package rungrouptest
+
+import (
+	"fmt"
+	"sync"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+// EventKind is the kind of an [Event].
+type EventKind int
+
+const (
+	// TaskStarted is recorded just before a task runs.
+	TaskStarted EventKind = iota + 1
+
+	// TaskFinished is recorded just after a task returns.
+	TaskFinished
+
+	// Canceled is recorded when the cancellation of the group is observed.
+	Canceled
+)
+
+// String returns the name of k.
+func (k EventKind) String() string {
+	switch k {
+	case TaskStarted:
+		return "TaskStarted"
+	case TaskFinished:
+		return "TaskFinished"
+	case Canceled:
+		return "Canceled"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// An Event is a lifecycle event of a [rungroup.Group] captured by a
+// [Recorder].
+type Event struct {
+	Kind EventKind
+
+	// Task is the task that started or finished. It is zero for Canceled.
+	Task rungroup.TaskID
+
+	// Err is the error returned by the task for TaskFinished, and the cause of
+	// the cancellation for Canceled.
+	Err error
+}
+
+// String returns a short description of e.
+func (e Event) String() string {
+	switch {
+	case e.Kind == Canceled:
+		return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+	case e.Err != nil:
+		return fmt.Sprintf("%v %v: %v", e.Kind, e.Task, e.Err)
+	}
+	return fmt.Sprintf("%v %v", e.Kind, e.Task)
+}
+
+// A Recorder captures the lifecycle events of a [rungroup.Group] in the order
+// they happen.
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewRecorder returns a Recorder that captures the events of gr.
+//
+// Only tasks started after NewRecorder returns are recorded.
+func NewRecorder(gr *rungroup.Group) *Recorder {
+	r := &Recorder{}
+	gr.AddHooks(rungroup.Hooks{
+		TaskStarted: func(id rungroup.TaskID) {
+			r.add(Event{Kind: TaskStarted, Task: id})
+		},
+		TaskFinished: func(id rungroup.TaskID, err error) {
+			r.add(Event{Kind: TaskFinished, Task: id, Err: err})
+		},
+		Canceled: func(cause error) {
+			r.add(Event{Kind: Canceled, Err: cause})
+		},
+	})
+	return r
+}
+
+// Events returns a copy of the events captured so far.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+func (r *Recorder) add(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
@@ -0,0 +1,137 @@
+// Package rungrouptest provides utilities for testing code that uses
+// [rungroup.Group].
+//
+// It includes a fake [Clock], a [Recorder] that captures the lifecycle events
+// of a group, a leak checker, and assertions about how a group and its tasks
+// ended.
+package rungrouptest
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+// LeakTimeout is how long the leak checker installed by [CheckLeaks] waits for
+// the tasks of a group to finish.
+var LeakTimeout = time.Second
+
+// CheckLeaks registers a function with t.Cleanup that reports an error if gr
+// leaks.
+//
+// A group leaks if it has not been canceled by the end of the test, which
+// means that [rungroup.Group.Close] or [rungroup.Group.Cancel] was never
+// called, or if any of its tasks are still running [LeakTimeout] after the end
+// of the test.
+//
+// Only tasks started after CheckLeaks returns are checked.
+func CheckLeaks(t testing.TB, gr *rungroup.Group) {
+	t.Helper()
+	var mu sync.Mutex
+	canceled := false
+	running := map[rungroup.TaskID]bool{}
+	gr.AddHooks(rungroup.Hooks{
+		TaskStarted: func(id rungroup.TaskID) {
+			mu.Lock()
+			defer mu.Unlock()
+			running[id] = true
+		},
+		TaskFinished: func(id rungroup.TaskID, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			delete(running, id)
+		},
+		Canceled: func(cause error) {
+			mu.Lock()
+			defer mu.Unlock()
+			canceled = true
+		},
+	})
+	t.Cleanup(func() {
+		if !finishWithin(gr, LeakTimeout) {
+			mu.Lock()
+			defer mu.Unlock()
+			ids := make([]rungroup.TaskID, 0, len(running))
+			for id := range running {
+				ids = append(ids, id)
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			t.Errorf("rungrouptest: tasks still running %v after the test: %v", LeakTimeout, ids)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !canceled {
+			t.Errorf("rungrouptest: group was not canceled; call Close or Cancel")
+		}
+	})
+}
+
+// AssertFinishedWithin waits for gr to finish, and returns the result of
+// [rungroup.Group.Wait].
+//
+// If gr does not finish within d, AssertFinishedWithin calls t.Fatalf.
+func AssertFinishedWithin(t testing.TB, gr *rungroup.Group, d time.Duration) error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- gr.Wait() }()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		t.Fatalf("rungrouptest: group did not finish within %v", d)
+		return nil
+	}
+}
+
+// AssertTaskCanceled reports an error if the task id was not running when the
+// group recorded by r was canceled, or if the cause of the cancellation does
+// not match cause according to [errors.Is].
+func AssertTaskCanceled(t testing.TB, r *Recorder, id rungroup.TaskID, cause error) {
+	t.Helper()
+	started, canceled := false, false
+	for _, e := range r.Events() {
+		switch {
+		case e.Kind == TaskStarted && e.Task == id:
+			started = true
+		case e.Kind == TaskFinished && e.Task == id:
+			if !canceled {
+				t.Errorf("rungrouptest: task %v finished before the group was canceled", id)
+			}
+			return
+		case e.Kind == Canceled:
+			if !started {
+				t.Errorf("rungrouptest: task %v was not running when the group was canceled", id)
+				return
+			}
+			if !errors.Is(e.Err, cause) {
+				t.Errorf("rungrouptest: task %v was canceled with %v, want %v", id, e.Err, cause)
+				return
+			}
+			canceled = true
+		}
+	}
+	if !canceled {
+		t.Errorf("rungrouptest: task %v was not canceled", id)
+	}
+}
+
+// finishWithin reports whether gr finishes within d.
+func finishWithin(gr *rungroup.Group, d time.Duration) bool {
+	done := make(chan struct{})
+	go func() { gr.Wait(); close(done) }()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
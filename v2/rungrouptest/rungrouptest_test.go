@@ -0,0 +1,113 @@
+package rungrouptest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/rungroup/v2/rungrouptest"
+)
+
+func ExampleRecorder() {
+	var gr rungroup.Group
+	defer gr.Close()
+	r := rungrouptest.NewRecorder(&gr)
+	gr.GoCancelOnError(func(ctx context.Context) error { return errors.New("failed") })
+	gr.Wait()
+	for _, e := range r.Events() {
+		fmt.Println(e.Kind)
+	}
+	// Output:
+	// TaskStarted
+	// TaskFinished
+	// Canceled
+}
+
+func TestAssertTaskCanceled(t *testing.T) {
+	ErrStop := errors.New("stop")
+	var gr rungroup.Group
+	defer gr.Close()
+	r := rungrouptest.NewRecorder(&gr)
+	ids := make(chan rungroup.TaskID, 1)
+	gr.Go(func(ctx context.Context) {
+		_, id, _ := rungroup.FromContext(ctx)
+		ids <- id
+		<-ctx.Done()
+	})
+	id := <-ids
+	gr.Cancel(ErrStop)
+	gr.Wait()
+	rungrouptest.AssertTaskCanceled(t, r, id, ErrStop)
+
+	tb := &fakeTB{TB: t}
+	rungrouptest.AssertTaskCanceled(tb, r, id, context.DeadlineExceeded)
+	tb.assertFailed(t, "was canceled with")
+}
+
+func TestCheckLeaks(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var gr rungroup.Group
+		rungrouptest.CheckLeaks(t, &gr)
+		defer gr.Close()
+		gr.Go(func(ctx context.Context) { <-ctx.Done() })
+	})
+
+	t.Run("not canceled", func(t *testing.T) {
+		tb := &fakeTB{TB: t}
+		var gr rungroup.Group
+		rungrouptest.CheckLeaks(tb, &gr)
+		gr.Go(func(ctx context.Context) {})
+		tb.cleanup()
+		tb.assertFailed(t, "not canceled")
+	})
+
+	t.Run("still running", func(t *testing.T) {
+		defer func(d time.Duration) { rungrouptest.LeakTimeout = d }(rungrouptest.LeakTimeout)
+		rungrouptest.LeakTimeout = 10 * time.Millisecond
+		tb := &fakeTB{TB: t}
+		var gr rungroup.Group
+		rungrouptest.CheckLeaks(tb, &gr)
+		release := make(chan struct{})
+		defer close(release)
+		gr.Go(func(ctx context.Context) { <-release })
+		gr.Close()
+		tb.cleanup()
+		tb.assertFailed(t, "still running")
+	})
+}
+
+// fakeTB is a testing.TB that captures errors and cleanup functions instead of
+// reporting them to the underlying test.
+type fakeTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Cleanup(f func()) { tb.cleanups = append(tb.cleanups, f) }
+
+func (tb *fakeTB) cleanup() {
+	for i := len(tb.cleanups) - 1; i >= 0; i-- {
+		tb.cleanups[i]()
+	}
+}
+
+func (tb *fakeTB) assertFailed(t *testing.T, substr string) {
+	t.Helper()
+	for _, s := range tb.errors {
+		if strings.Contains(s, substr) {
+			return
+		}
+	}
+	t.Errorf("want an error containing %q, got %q", substr, tb.errors)
+}
@@ -0,0 +1,104 @@
+package rungroup
+
+import (
+	"context"
+	"reflect"
+)
+
+// GoShared runs task in gr for the key, and returns its result, coalescing the
+// concurrent calls for the same key into one execution.
+//
+// If a task for the key is running, GoShared does not start task, but waits
+// for the running task and returns its result as well. Otherwise GoShared
+// starts task as [Group.Go] does, so the task is tracked by [Group.Wait] and
+// its context is canceled together with the [Group]. Once the task has
+// returned, the next call for the key starts a new task. Calls with the same
+// key but a different type T do not share their tasks.
+//
+// If ctx is done before the task returns, GoShared gives up waiting and
+// returns the cause of ctx. The task keeps running for the other callers, and
+// is canceled only if every caller waiting for it has given up.
+//
+// If the task panics, the [Group] is canceled as it is for any task, and
+// GoShared returns a [*PanicError].
+//
+// Use Cases:
+//
+// Use this to deduplicate expensive work, such as filling a cache entry that
+// many requests miss at the same time.
+func GoShared[T any](ctx context.Context, gr *Group, key string, task func(context.Context) (T, error)) (T, error) {
+	k := sharedKey{key: key, typ: reflect.TypeOf((*T)(nil))}
+	parent := gr.getContext()
+	gr.mu.Lock()
+	c, running := gr.shared[k]
+	var taskCtx context.Context
+	if !running {
+		var cancel context.CancelCauseFunc
+		taskCtx, cancel = context.WithCancelCause(parent)
+		c = &sharedCall{handle: &Task{gr: gr, cancel: cancel, done: make(chan struct{})}}
+		if gr.shared == nil {
+			gr.shared = map[sharedKey]*sharedCall{}
+		}
+		gr.shared[k] = c
+	}
+	c.waiters++
+	gr.mu.Unlock()
+
+	if !running {
+		c.handle.id = gr.goTask(taskSpec{
+			callers: gr.taskCallers(CancelNever, 1),
+			ctx:     taskCtx,
+			handle:  c.handle,
+		}, func(ctx context.Context) error {
+			defer gr.forgetShared(k, c)
+			v, err := task(ctx)
+			c.value = v
+			return err
+		})
+	}
+
+	select {
+	case <-c.handle.done:
+		v, _ := c.value.(T)
+		return v, c.handle.err
+	case <-ctx.Done():
+	}
+	gr.mu.Lock()
+	c.waiters--
+	last := c.waiters == 0
+	if last && gr.shared[k] == c {
+		// Forget the task before unlocking, so that no caller joins it
+		// after it is canceled.
+		delete(gr.shared, k)
+	}
+	gr.mu.Unlock()
+	if last {
+		c.handle.cancel(context.Cause(ctx))
+	}
+	var zero T
+	return zero, context.Cause(ctx)
+}
+
+// sharedKey identifies the tasks started by [GoShared].
+type sharedKey struct {
+	key string
+	typ reflect.Type
+}
+
+// sharedCall is a task started by [GoShared], along with the callers waiting
+// for it.
+type sharedCall struct {
+	handle  *Task
+	value   any // result of the task, set before handle is finished
+	waiters int // protected by Group.mu
+}
+
+// forgetShared removes c, the task for k, so that the next call for k starts
+// a new task.
+func (gr *Group) forgetShared(k sharedKey, c *sharedCall) {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+	if gr.shared[k] == c {
+		delete(gr.shared, k)
+	}
+}
@@ -0,0 +1,128 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestGoShared(t *testing.T) {
+	t.Run("coalesced", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		var calls atomic.Int64
+		release := make(chan struct{})
+		task := func(context.Context) (int, error) {
+			calls.Add(1)
+			<-release
+			return 42, nil
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := rungroup.GoShared(context.TODO(), &gr, "key", task)
+				assertNoError(t, err)
+				assertEqual(t, v, 42)
+			}()
+		}
+		time.Sleep(10 * time.Millisecond) // let the callers join the task
+		close(release)
+		wg.Wait()
+		assertEqual(t, calls.Load(), int64(1))
+
+		// Once the task has returned, the next call starts a new task, and a
+		// different type does not share the task.
+		v, err := rungroup.GoShared(context.TODO(), &gr, "key", func(context.Context) (int, error) { return 7, nil })
+		assertNoError(t, err)
+		assertEqual(t, v, 7)
+		s, err := rungroup.GoShared(context.TODO(), &gr, "key", func(context.Context) (string, error) { return "s", nil })
+		assertNoError(t, err)
+		assertEqual(t, s, "s")
+		assertEqual(t, gr.Stats().Started, int64(3))
+	})
+
+	t.Run("waiter gives up", func(t *testing.T) {
+		ErrGiveUp := errors.New("give up")
+		var gr rungroup.Group
+		defer gr.Close()
+		release := make(chan struct{})
+		task := func(ctx context.Context) (string, error) {
+			select {
+			case <-release:
+				return "done", nil
+			case <-ctx.Done():
+				return "", context.Cause(ctx)
+			}
+		}
+
+		ctx, cancel := context.WithCancelCause(context.TODO())
+		first := make(chan error, 1)
+		go func() {
+			_, err := rungroup.GoShared(ctx, &gr, "key", task)
+			first <- err
+		}()
+		second := make(chan string, 1)
+		go func() {
+			v, _ := rungroup.GoShared(context.TODO(), &gr, "key", task)
+			second <- v
+		}()
+		time.Sleep(10 * time.Millisecond) // let both callers join the task
+		cancel(ErrGiveUp)
+		assertErrorIs(t, <-first, ErrGiveUp)
+		close(release)
+		assertEqual(t, <-second, "done")
+	})
+
+	t.Run("last waiter gives up", func(t *testing.T) {
+		ErrGiveUp := errors.New("give up")
+		var gr rungroup.Group
+		defer gr.Close()
+		ctx, cancel := context.WithCancelCause(context.TODO())
+		canceled := make(chan error, 1)
+		go func() {
+			<-time.After(time.Millisecond)
+			cancel(ErrGiveUp)
+		}()
+		_, err := rungroup.GoShared(ctx, &gr, "key", func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			canceled <- context.Cause(ctx)
+			return 0, nil
+		})
+		assertErrorIs(t, err, ErrGiveUp)
+
+		// A new call does not join the canceled task.
+		v, err := rungroup.GoShared(context.TODO(), &gr, "key", func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+		assertNoError(t, err)
+		assertEqual(t, v, 1)
+		assertErrorIs(t, <-canceled, ErrGiveUp)
+	})
+
+	t.Run("canceled with the group", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		var gr rungroup.Group
+		gr.Go(func(context.Context) { gr.Cancel(ErrStop) })
+		_, err := rungroup.GoShared(context.TODO(), &gr, "key", func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, context.Cause(ctx)
+		})
+		assertErrorIs(t, err, ErrStop)
+		assertErrorIs(t, gr.Wait(), ErrStop)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		var gr rungroup.Group
+		_, err := rungroup.GoShared(context.TODO(), &gr, "key", func(context.Context) (int, error) { panic("boom") })
+		var pe *rungroup.PanicError
+		assertEqual(t, errors.As(err, &pe), true)
+		gr.Wait()
+	})
+}
@@ -0,0 +1,233 @@
+package rungroup
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats holds statistics about the tasks of a [Group].
+//
+// The counters are updated independently of each other, so a Stats taken while
+// tasks are starting or finishing may be slightly inconsistent, e.g. Running
+// may not equal Started minus the finished tasks.
+type Stats struct {
+	Started   int64 // Number of tasks that have started running.
+	Running   int64 // Number of tasks that are running.
+	Succeeded int64 // Number of tasks that returned a nil error.
+	Failed    int64 // Number of tasks that returned a non-nil error.
+	Panicked  int64 // Number of tasks that panicked.
+	Rejected  int64 // Number of tasks that were not started because the Group was draining.
+	Skipped   int64 // Number of tasks queued by GoKeyed that were not started because the Group was canceled.
+
+	// Limit is the current limit set by [Group.SetAdaptiveLimit], or zero if
+	// there is none. LimitIncreases and LimitDecreases count how many times
+	// the limit has changed.
+	Limit          int
+	LimitIncreases int64
+	LimitDecreases int64
+
+	// TimeToCancel summarizes how long the tasks that were running when the
+	// [Group] was canceled took to return after the cancellation.
+	TimeToCancel DurationStats
+
+	// Tasks holds the statistics of the finished tasks by name. Tasks
+	// started without a name are counted under the empty name.
+	Tasks map[string]TaskStats
+}
+
+// TaskStats holds statistics about the finished tasks of one name.
+type TaskStats struct {
+	// Duration summarizes how long the tasks ran.
+	Duration DurationStats
+
+	// TimeToCancel summarizes how long the tasks that were running when the
+	// [Group] was canceled took to return after the cancellation.
+	TimeToCancel DurationStats
+
+	// Circuit is the state of the circuit breaker of the name, set by
+	// [Group.SetCircuitBreaker]. CircuitOpened counts how many times it
+	// opened, and CircuitRejected how many tasks failed fast because it was
+	// open.
+	Circuit         CircuitState
+	CircuitOpened   int64
+	CircuitRejected int64
+}
+
+// DurationStats summarizes a set of durations.
+type DurationStats struct {
+	Count int64
+	Min   time.Duration
+	Max   time.Duration
+	Mean  time.Duration
+
+	// Buckets is a histogram of the durations. The upper bounds of the
+	// buckets grow by a factor of 10 from 1µs to 100s, and the last bucket
+	// holds the durations greater than 100s.
+	Buckets []Bucket
+}
+
+// A Bucket is a bucket of the histogram in [DurationStats].
+type Bucket struct {
+	// Le is the inclusive upper bound of the bucket. It is the maximum
+	// duration for the last bucket.
+	Le time.Duration
+
+	// Count is the number of durations greater than Le of the previous
+	// bucket, and less than or equal to Le.
+	Count int64
+}
+
+// bucketBounds are the upper bounds of the buckets of a histogram.
+var bucketBounds = [...]time.Duration{
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+	100 * time.Second,
+	math.MaxInt64,
+}
+
+// Stats returns the statistics of the tasks of the [Group].
+func (gr *Group) Stats() Stats {
+	s := &gr.stats
+	st := Stats{
+		Started:      s.started.Load(),
+		Succeeded:    s.succeeded.Load(),
+		Failed:       s.failed.Load(),
+		Panicked:     s.panicked.Load(),
+		Rejected:     s.rejected.Load(),
+		Skipped:      s.skipped.Load(),
+		TimeToCancel: s.timeToCancel.get(),
+		Tasks:        map[string]TaskStats{},
+	}
+	st.Running = st.Started - s.finished.Load()
+	st.Limit, st.LimitIncreases, st.LimitDecreases = gr.adaptive.Load().stats()
+	s.tasks.Range(func(key, value any) bool {
+		ts := value.(*taskStats)
+		st.Tasks[key.(string)] = TaskStats{
+			Duration:     ts.duration.get(),
+			TimeToCancel: ts.timeToCancel.get(),
+		}
+		return true
+	})
+	gr.breaker.Load().stats(st.Tasks)
+	return st
+}
+
+// stats holds the statistics of a [Group].
+// It is updated with atomic operations only.
+type stats struct {
+	started   atomic.Int64
+	finished  atomic.Int64
+	succeeded atomic.Int64
+	failed    atomic.Int64
+	panicked  atomic.Int64
+	rejected  atomic.Int64
+	skipped   atomic.Int64
+
+	canceledAt   atomic.Int64 // UnixNano of the cancellation, or zero
+	timeToCancel durationStats
+
+	tasks sync.Map // map[string]*taskStats
+}
+
+// taskStats holds the statistics of the tasks of one name.
+type taskStats struct {
+	duration     durationStats
+	timeToCancel durationStats
+}
+
+// start records the start of a task, and returns the time of the start.
+func (s *stats) start(clock Clock) time.Time {
+	s.started.Add(1)
+	return clock.Now()
+}
+
+// finish records the end of the task named name which started at start, and
+// returns the time of the end.
+func (s *stats) finish(clock Clock, name string, start time.Time, err error, panicked bool) time.Time {
+	end := clock.Now()
+	switch {
+	case panicked:
+		s.panicked.Add(1)
+	case err != nil:
+		s.failed.Add(1)
+	default:
+		s.succeeded.Add(1)
+	}
+	s.finished.Add(1)
+
+	v, ok := s.tasks.Load(name)
+	if !ok {
+		v, _ = s.tasks.LoadOrStore(name, &taskStats{})
+	}
+	ts := v.(*taskStats)
+	ts.duration.add(end.Sub(start))
+	if at := s.canceledAt.Load(); at != 0 && at >= start.UnixNano() && at <= end.UnixNano() {
+		d := end.Sub(time.Unix(0, at))
+		ts.timeToCancel.add(d)
+		s.timeToCancel.add(d)
+	}
+	return end
+}
+
+// cancel records the time of the cancellation of the [Group].
+func (s *stats) cancel(clock Clock) {
+	s.canceledAt.CompareAndSwap(0, clock.Now().UnixNano())
+}
+
+// durationStats summarizes durations with atomic operations.
+type durationStats struct {
+	count   atomic.Int64
+	sum     atomic.Int64
+	min     atomic.Int64 // stored as ^min, so that the zero value means no minimum
+	max     atomic.Int64
+	buckets [len(bucketBounds)]atomic.Int64
+}
+
+func (s *durationStats) add(d time.Duration) {
+	s.count.Add(1)
+	s.sum.Add(int64(d))
+	for {
+		old := s.min.Load()
+		if old != 0 && ^old <= int64(d) || s.min.CompareAndSwap(old, ^int64(d)) {
+			break
+		}
+	}
+	for {
+		old := s.max.Load()
+		if old >= int64(d) || s.max.CompareAndSwap(old, int64(d)) {
+			break
+		}
+	}
+	for i, le := range bucketBounds {
+		if d <= le {
+			s.buckets[i].Add(1)
+			break
+		}
+	}
+}
+
+func (s *durationStats) get() DurationStats {
+	ds := DurationStats{
+		Count:   s.count.Load(),
+		Max:     time.Duration(s.max.Load()),
+		Buckets: make([]Bucket, len(bucketBounds)),
+	}
+	if min := s.min.Load(); min != 0 {
+		ds.Min = time.Duration(^min)
+	}
+	if ds.Count > 0 {
+		ds.Mean = time.Duration(s.sum.Load() / ds.Count)
+	}
+	for i, le := range bucketBounds {
+		ds.Buckets[i] = Bucket{Le: le, Count: s.buckets[i].Load()}
+	}
+	return ds
+}
@@ -0,0 +1,141 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/rungroup/v2/rungrouptest"
+)
+
+func ExampleGroup_Stats() {
+	var gr rungroup.Group
+	defer gr.Close()
+	gr.GoNamed("fetch", rungroup.CancelNever, func(context.Context) error { return nil })
+	gr.GoNamed("fetch", rungroup.CancelNever, func(context.Context) error { return errors.New("failed") })
+	gr.Go(func(context.Context) {})
+	gr.Wait()
+	st := gr.Stats()
+	fmt.Println(st.Started, st.Running, st.Succeeded, st.Failed, st.Panicked)
+	fmt.Println(st.Tasks["fetch"].Duration.Count, st.Tasks[""].Duration.Count)
+	// Output:
+	// 3 0 2 1 0
+	// 2 1
+}
+
+func TestGroup_GoNamed(t *testing.T) {
+	ErrStop := errors.New("stop")
+	for _, tt := range []struct {
+		policy rungroup.Policy
+		err    error
+		want   error
+	}{
+		{rungroup.CancelNever, ErrStop, nil},
+		{rungroup.CancelOnFinish, nil, context.Canceled},
+		{rungroup.CancelOnFinish, ErrStop, ErrStop},
+		{rungroup.CancelOnSuccess, nil, context.Canceled},
+		{rungroup.CancelOnSuccess, ErrStop, nil},
+		{rungroup.CancelOnError, nil, nil},
+		{rungroup.CancelOnError, ErrStop, ErrStop},
+	} {
+		t.Run(fmt.Sprint(tt.policy, tt.err), func(t *testing.T) {
+			var gr rungroup.Group
+			defer gr.Close()
+			gr.GoNamed("task", tt.policy, func(context.Context) error { return tt.err })
+			err := gr.Wait()
+			if tt.want == nil {
+				assertNoError(t, err)
+			} else {
+				assertErrorIs(t, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroup_Stats(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.Go(func(context.Context) { panic("boom") })
+		err := gr.Wait()
+		var perr *rungroup.PanicError
+		if !errors.As(err, &perr) {
+			t.Fatalf("err=%#v, want *PanicError", err)
+		}
+		assertEqual(t, perr.Value, any("boom"))
+		assertEqual(t, perr.Error(), "panic: boom")
+		assertEqual(t, gr.Stats().Panicked, 1)
+	})
+
+	t.Run("panic after cancel", func(t *testing.T) {
+		for join, panics := range map[bool]int{false: 1, true: 2} {
+			var gr rungroup.Group
+			gr.SetJoinCauses(join)
+			gr.Go(func(ctx context.Context) { <-ctx.Done(); panic("boom") })
+			gr.Go(func(ctx context.Context) { <-ctx.Done(); panic("second") })
+			gr.Close()
+			err := gr.Wait()
+			assertErrorIs(t, err, rungroup.ErrClosed)
+			var perr *rungroup.PanicError
+			if !errors.As(err, &perr) {
+				t.Fatalf("join=%v: err=%#v, want *PanicError", join, err)
+			}
+			// Each panic is reported once, even when the causes are joined.
+			assertEqual(t, strings.Count(err.Error(), "panic: "), panics)
+		}
+	})
+
+	t.Run("durations", func(t *testing.T) {
+		clock := rungrouptest.NewClock(time.Unix(0, 0))
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetClock(clock)
+		started := make(chan struct{})
+		for i := 1; i <= 3; i++ {
+			d := time.Duration(i) * time.Second
+			gr.GoNamed("sleep", rungroup.CancelNever, func(ctx context.Context) error {
+				started <- struct{}{}
+				<-clock.NewTimer(d).C()
+				return nil
+			})
+		}
+		gr.GoNamed("wait", rungroup.CancelNever, func(ctx context.Context) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			clock.Advance(5 * time.Second)
+			return ctx.Err()
+		})
+		for i := 0; i < 4; i++ {
+			<-started
+		}
+		clock.BlockUntil(3)
+		for i := 1; i <= 3; i++ {
+			clock.Advance(time.Second)
+			for gr.Stats().Succeeded != int64(i) {
+				time.Sleep(time.Millisecond)
+			}
+		}
+		gr.Close()
+		gr.Wait()
+
+		st := gr.Stats()
+		assertEqual(t, st.Started, 4)
+		assertEqual(t, st.Running, 0)
+		assertEqual(t, st.Succeeded, 3)
+		assertEqual(t, st.Failed, 1)
+		sleep := st.Tasks["sleep"].Duration
+		assertEqual(t, sleep.Count, 3)
+		assertEqual(t, sleep.Min, time.Second)
+		assertEqual(t, sleep.Max, 3*time.Second)
+		assertEqual(t, sleep.Mean, 2*time.Second)
+		assertEqual(t, sleep.Buckets[6], rungroup.Bucket{Le: time.Second, Count: 1})
+		assertEqual(t, sleep.Buckets[7], rungroup.Bucket{Le: 10 * time.Second, Count: 2})
+		assertEqual(t, st.Tasks["sleep"].TimeToCancel.Count, 0)
+		assertEqual(t, st.Tasks["wait"].TimeToCancel.Max, 5*time.Second)
+		assertEqual(t, st.TimeToCancel.Count, 1)
+	})
+}
@@ -0,0 +1,84 @@
+package rungroup
+
+import (
+	"context"
+
+	"github.com/goaux/stacktrace/v2"
+)
+
+// A Task is a handle of a task started by [Group.GoTask], which can cancel and
+// wait for that task alone.
+type Task struct {
+	gr     *Group
+	id     TaskID
+	cancel context.CancelCauseFunc
+	done   chan struct{}
+	err    error
+}
+
+// GoTask starts a task named name like [Group.GoNamed] does, and returns a
+// handle of the task.
+//
+// The task runs with its own context, derived from the [Group]'s context,
+// which [Task.Cancel] cancels. Canceling a task does not cancel the [Group] by
+// itself; the [Group] is canceled only if policy says so for the error that
+// the task returns.
+func (gr *Group) GoTask(name string, policy Policy, task func(context.Context) error) *Task {
+	ctx, cancel := context.WithCancelCause(gr.getContext())
+	t := &Task{gr: gr, cancel: cancel, done: make(chan struct{})}
+	t.id = gr.goTask(taskSpec{
+		name:    name,
+		callers: gr.taskCallers(policy, 1),
+		policy:  policy,
+		ctx:     ctx,
+		handle:  t,
+	}, task)
+	return t
+}
+
+// ID returns the ID of the task.
+func (t *Task) ID() TaskID {
+	return t.id
+}
+
+// Cancel cancels the context of the task with cause.
+//
+// As with [Group.Cancel], the first cause is recorded, with the call site of
+// Cancel, and a nil cause is replaced with [context.Canceled].
+func (t *Task) Cancel(cause error) {
+	if cause == nil {
+		cause = context.Canceled
+	}
+	t.cancel(stacktrace.NewError(cause, t.gr.callers(1)))
+}
+
+// Done returns a channel that is closed when the task has finished.
+func (t *Task) Done() <-chan struct{} {
+	return t.done
+}
+
+// Wait blocks until the task has finished, and returns the error returned by
+// the task.
+func (t *Task) Wait() error {
+	<-t.done
+	return t.err
+}
+
+// Err returns the error returned by the task, or a [*PanicError] if the task
+// panicked. It returns nil while the task is running.
+func (t *Task) Err() error {
+	select {
+	case <-t.done:
+		return t.err
+	default:
+		return nil
+	}
+}
+
+// finish records the error returned by the task, and releases the context of
+// the task.
+func (t *Task) finish(err error) {
+	t.err = err
+	t.cancel(context.Canceled)
+	close(t.done)
+}
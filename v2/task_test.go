@@ -0,0 +1,77 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func ExampleTask() {
+	var gr rungroup.Group
+	defer gr.Close()
+	t := gr.GoTask("poll", rungroup.CancelNever, func(ctx context.Context) error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+	t.Cancel(errors.New("no longer needed"))
+	fmt.Println(t.Wait())
+	fmt.Println(gr.Wait())
+	// Output:
+	// no longer needed (task_test.go:19 ExampleTask)
+	// <nil>
+}
+
+func TestTask(t *testing.T) {
+	t.Run("cancel one task", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		other := gr.GoTask("other", rungroup.CancelNever, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		task := gr.GoTask("task", rungroup.CancelNever, func(ctx context.Context) error {
+			<-ctx.Done()
+			return context.Cause(ctx)
+		})
+		assertNoError(t, task.Err())
+		task.Cancel(nil)
+		<-task.Done()
+		assertErrorIs(t, task.Err(), context.Canceled)
+		select {
+		case <-other.Done():
+			t.Error("the other task must keep running")
+		default:
+		}
+		gr.Close()
+		assertErrorIs(t, other.Wait(), context.Canceled)
+		assertErrorIs(t, gr.Wait(), rungroup.ErrClosed)
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		var gr rungroup.Group
+		defer gr.Close()
+		task := gr.GoTask("task", rungroup.CancelOnError, func(ctx context.Context) error {
+			<-ctx.Done()
+			return context.Cause(ctx)
+		})
+		task.Cancel(ErrStop)
+		assertErrorIs(t, task.Wait(), ErrStop)
+		assertErrorIs(t, gr.Wait(), ErrStop)
+	})
+
+	t.Run("ID", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		var id rungroup.TaskID
+		task := gr.GoTask("", rungroup.CancelNever, func(ctx context.Context) error {
+			_, id, _ = rungroup.FromContext(ctx)
+			return nil
+		})
+		task.Wait()
+		assertEqual(t, task.ID(), id)
+	})
+}
@@ -0,0 +1,148 @@
+package rungroup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/goaux/stacktrace/v2"
+)
+
+// ErrStalled is matched, with [errors.Is], by the cause with which a task
+// started by [Group.GoWatched] is canceled when it stops sending heartbeats.
+var ErrStalled = errors.New("stalled")
+
+// A StalledError is the cause with which the context of a task started by
+// [Group.GoWatched] is canceled when the task does not call heartbeat within
+// the timeout.
+type StalledError struct {
+	// Timeout is the timeout passed to [Group.GoWatched].
+	Timeout time.Duration
+
+	// Stack is the stack trace of the goroutine of the task at the time of
+	// the stall, in the format of [runtime.Stack].
+	Stack []byte
+}
+
+// Error returns a description of the stall.
+func (e *StalledError) Error() string {
+	return fmt.Sprintf("%v: no heartbeat for %v", ErrStalled, e.Timeout)
+}
+
+// Unwrap returns [ErrStalled].
+func (e *StalledError) Unwrap() error {
+	return ErrStalled
+}
+
+// GoWatched starts a task that must call heartbeat at least once every
+// timeout, and cancels the [Group] upon completion of the task according to
+// policy, as [Group.GoNamed] does.
+//
+// The task runs with its own context, derived from the [Group]'s context. If
+// the task does not call heartbeat within timeout after it started or after
+// the previous call, its context is canceled with a [*StalledError], which
+// holds the stack trace of the task's goroutine.
+//
+// A stall counts as an error for policy: with [CancelOnError] or
+// [CancelOnFinish], the [Group] is canceled with the [*StalledError] as well,
+// without waiting for the task to return. With [CancelNever] or
+// [CancelOnSuccess], only the task is canceled.
+//
+// Use Cases:
+//
+// Use this for tasks that may hang without exiting, for example a consumer
+// stuck on a broken connection.
+func (gr *Group) GoWatched(timeout time.Duration, policy Policy, task func(ctx context.Context, heartbeat func()) error) {
+	callers := gr.callers(1)
+	gr.goTask(taskSpec{callers: callers, policy: policy}, func(ctx context.Context) error {
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+		beats := make(chan struct{}, 1)
+		done := make(chan struct{})
+		stopped := make(chan struct{})
+		defer func() {
+			// Wait for the watcher, so that it does not cancel the Group
+			// after Wait has returned.
+			close(done)
+			<-stopped
+		}()
+		goid := goroutineID()
+		go func() {
+			defer close(stopped)
+			gr.watch(timeout, goid, beats, done, func(err *StalledError) {
+				cancel(err)
+				if policy == CancelOnError || policy == CancelOnFinish {
+					gr.cancelCause(stacktrace.NewError(err, callers))
+				}
+			})
+		}()
+		return task(ctx, func() {
+			select {
+			case beats <- struct{}{}:
+			default:
+			}
+		})
+	})
+}
+
+// watch calls stalled if nothing is received from beats for timeout, until
+// done is closed. goid is the ID of the goroutine of the watched task.
+func (gr *Group) watch(timeout time.Duration, goid uint64, beats <-chan struct{}, done <-chan struct{}, stalled func(*StalledError)) {
+	clock := gr.getClock()
+	for {
+		t := clock.NewTimer(timeout)
+		select {
+		case <-beats:
+			t.Stop()
+		case <-t.C():
+			select {
+			case <-done:
+				// The task returned as the timer fired.
+				return
+			default:
+			}
+			stalled(&StalledError{Timeout: timeout, Stack: goroutineStack(goid)})
+			return
+		case <-done:
+			t.Stop()
+			return
+		}
+	}
+}
+
+// goroutineID returns the ID of the calling goroutine.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i != -1 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// goroutineStack returns the stack trace of the goroutine whose ID is goid, or
+// nil if there is no such goroutine.
+func goroutineStack(goid uint64) []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	prefix := []byte("goroutine " + strconv.FormatUint(goid, 10) + " [")
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		if bytes.HasPrefix(stack, prefix) {
+			return stack
+		}
+	}
+	return nil
+}
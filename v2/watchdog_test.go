@@ -0,0 +1,94 @@
+package rungroup_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+	"github.com/goaux/rungroup/v2/rungrouptest"
+)
+
+func TestGroup_GoWatched(t *testing.T) {
+	t.Run("heartbeat", func(t *testing.T) {
+		clock := rungrouptest.NewClock(time.Unix(0, 0))
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetClock(clock)
+		gr.GoWatched(time.Second, rungroup.CancelOnError, func(ctx context.Context, heartbeat func()) error {
+			for i := 0; i < 3; i++ {
+				clock.BlockUntil(1)
+				clock.Advance(time.Second / 4)
+				heartbeat()
+			}
+			return ctx.Err()
+		})
+		assertNoError(t, gr.Wait())
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		clock := rungrouptest.NewClock(time.Unix(0, 0))
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetClock(clock)
+		var cause error
+		gr.GoWatched(time.Second, rungroup.CancelNever, func(ctx context.Context, heartbeat func()) error {
+			stuck(ctx)
+			cause = context.Cause(ctx)
+			return cause
+		})
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+		assertNoError(t, gr.Wait())
+		assertErrorIs(t, cause, rungroup.ErrStalled)
+		var serr *rungroup.StalledError
+		if !errors.As(cause, &serr) {
+			t.Fatalf("cause=%#v, want *StalledError", cause)
+		}
+		assertEqual(t, serr.Timeout, time.Second)
+		if !bytes.Contains(serr.Stack, []byte("v2_test.stuck(")) {
+			t.Errorf("stack does not contain the stuck function:\n%s", serr.Stack)
+		}
+	})
+
+	t.Run("stall cancels the group", func(t *testing.T) {
+		clock := rungrouptest.NewClock(time.Unix(0, 0))
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetClock(clock)
+		release := make(chan struct{})
+		gr.GoWatched(time.Second, rungroup.CancelOnError, func(ctx context.Context, heartbeat func()) error {
+			<-release // ignores ctx
+			return nil
+		})
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+		time.AfterFunc(10*time.Millisecond, func() { close(release) })
+		assertErrorIs(t, gr.Wait(), rungroup.ErrStalled)
+	})
+
+	t.Run("returns as the timer fires", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			clock := rungrouptest.NewClock(time.Unix(0, 0))
+			var gr rungroup.Group
+			gr.SetClock(clock)
+			gr.GoWatched(time.Second, rungroup.CancelOnError, func(ctx context.Context, heartbeat func()) error {
+				clock.BlockUntil(1)
+				clock.Advance(time.Second)
+				return nil
+			})
+			gr.Wait()
+			n := len(gr.CancelHistory())
+			// The watcher has exited: nothing cancels the group after Wait.
+			time.Sleep(time.Millisecond)
+			assertEqual(t, len(gr.CancelHistory()), n)
+		}
+	})
+}
+
+// stuck blocks until ctx is done.
+func stuck(ctx context.Context) {
+	<-ctx.Done()
+}
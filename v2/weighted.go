@@ -0,0 +1,134 @@
+package rungroup
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// SetCapacity sets the capacity of the [Group] for the tasks started with
+// [Group.GoWeighted] to n units. A negative value indicates no capacity limit,
+// which is the default.
+//
+// Changing the capacity does not affect the tasks that are already admitted,
+// nor the tasks that are waiting for admission.
+//
+// Use Cases:
+//
+// Use this when tasks vary in cost, for example when some of them need 1 unit
+// of a memory budget and others need 50, which a limit on the number of tasks
+// set by [Group.SetLimit] does not capture.
+func (gr *Group) SetCapacity(n int) {
+	if n < 0 {
+		gr.capacity.Store(nil)
+		return
+	}
+	gr.capacity.Store(&weighted{size: n})
+}
+
+// GoWeighted starts a task using [Group.Go] once w units of the capacity set
+// by [Group.SetCapacity] are available, and holds them until the task
+// returns.
+//
+// GoWeighted blocks until the units are available. If the [Group] is canceled
+// before that, GoWeighted returns without starting the task. Tasks are
+// admitted in the order in which they called GoWeighted, so a heavy task is
+// not starved by lighter ones started after it. A weight larger than the
+// capacity is reduced to the capacity, so that the task runs alone.
+func (gr *Group) GoWeighted(w int, task func(context.Context)) {
+	gr.goTask(taskSpec{callers: gr.taskCallers(CancelNever, 1), fn: task, weight: w}, nil)
+}
+
+// acquireWeight reserves w units of the capacity set by SetCapacity, waiting
+// for them until ctx is done. It returns the function that frees the units,
+// and reports whether the units have been reserved.
+func (gr *Group) acquireWeight(ctx context.Context, w int) (release func(), ok bool) {
+	s := gr.capacity.Load()
+	if s == nil || w <= 0 {
+		return func() {}, true
+	}
+	if w > s.size {
+		w = s.size
+	}
+	if ctx.Err() != nil || !s.acquire(ctx, w) {
+		return nil, false
+	}
+	return func() { s.release(w) }, true
+}
+
+// weighted is a FIFO weighted semaphore.
+type weighted struct {
+	mu      sync.Mutex
+	size    int
+	used    int
+	waiters list.List // of *waiter, in the order of arrival
+}
+
+// waiter is a caller of weighted.acquire waiting for its units.
+type waiter struct {
+	n     int
+	ready chan struct{} // closed when the units are reserved
+}
+
+// acquire reserves n units, waiting for them until ctx is done, and reports
+// whether they have been reserved. Once ctx is done, acquire does not reserve
+// the units even if they become available at the same time.
+func (s *weighted) acquire(ctx context.Context, n int) bool {
+	s.mu.Lock()
+	if s.size-s.used >= n && s.waiters.Len() == 0 {
+		s.used += n
+		s.mu.Unlock()
+		return true
+	}
+	w := &waiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		if ctx.Err() == nil {
+			return true
+		}
+	case <-ctx.Done():
+	}
+	s.mu.Lock()
+	select {
+	case <-w.ready:
+		// The units were reserved while ctx was being canceled; give them
+		// back to the others.
+		s.used -= n
+	default:
+		s.waiters.Remove(elem)
+	}
+	s.notify()
+	s.mu.Unlock()
+	return false
+}
+
+// release frees n units.
+func (s *weighted) release(n int) {
+	s.mu.Lock()
+	s.used -= n
+	s.notify()
+	s.mu.Unlock()
+}
+
+// notify reserves the units for the waiters at the front of the queue, as long
+// as they fit.
+//
+// s.mu must be held.
+func (s *weighted) notify() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+		w := front.Value.(*waiter)
+		if s.size-s.used < w.n {
+			return
+		}
+		s.used += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
@@ -0,0 +1,62 @@
+package rungroup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rungroup "github.com/goaux/rungroup/v2"
+)
+
+func TestGroup_GoWeighted(t *testing.T) {
+	t.Run("no capacity", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		done := make(chan struct{})
+		gr.GoWeighted(100, func(context.Context) { close(done) })
+		<-done
+	})
+
+	t.Run("fifo", func(t *testing.T) {
+		var gr rungroup.Group
+		defer gr.Close()
+		gr.SetCapacity(10)
+
+		releaseFirst := make(chan struct{})
+		started := make(chan string, 3)
+		gr.GoWeighted(8, func(context.Context) { started <- "first"; <-releaseFirst })
+		<-started
+
+		// The heavy task waits for the first one to finish, and the light one,
+		// which would fit beside the first one, waits for the heavy one.
+		releaseHeavy := make(chan struct{})
+		go gr.GoWeighted(50, func(context.Context) { started <- "heavy"; <-releaseHeavy })
+		time.Sleep(10 * time.Millisecond) // let the heavy task queue up
+		go gr.GoWeighted(1, func(context.Context) { started <- "light" })
+		time.Sleep(10 * time.Millisecond)
+		assertEqual(t, len(started), 0)
+
+		close(releaseFirst)
+		assertEqual(t, <-started, "heavy")
+		time.Sleep(10 * time.Millisecond)
+		assertEqual(t, len(started), 0)
+		close(releaseHeavy)
+		assertEqual(t, <-started, "light")
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		ErrStop := errors.New("stop")
+		var gr rungroup.Group
+		gr.SetCapacity(1)
+		gr.GoWeighted(1, func(ctx context.Context) { <-ctx.Done() })
+		done := make(chan struct{})
+		go func() {
+			gr.GoWeighted(1, func(context.Context) { t.Error("started after the cancellation") })
+			close(done)
+		}()
+		gr.Cancel(ErrStop)
+		<-done
+		assertErrorIs(t, gr.Wait(), ErrStop)
+	})
+}